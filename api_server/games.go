@@ -0,0 +1,100 @@
+package api_server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// DefaultGameID is the game that receives submissions without a game_id when
+// the server hosts a registry.
+const DefaultGameID = "default"
+
+var (
+	// ErrGameExists is returned when creating a game whose ID is taken.
+	ErrGameExists = errors.New("game already exists")
+	// ErrGameNotFound is returned when no game has the requested ID.
+	ErrGameNotFound = errors.New("game not found")
+)
+
+// GameRegistry hosts independent games keyed by ID. CreateGame fails with an
+// error wrapping ErrGameExists if the ID is taken, and RemoveGame with one
+// wrapping ErrGameNotFound if it is unknown; any other error is a failure of
+// the game itself.
+type GameRegistry interface {
+	Lookup(id string) (GameEngineInterface, bool)
+	CreateGame(id string) (GameEngineInterface, error)
+	RemoveGame(id string) error
+	GameIDs() []string
+}
+
+// resolveGame returns the engine a submission for gameID should go to.
+func (s *APIServer) resolveGame(gameID string) (GameEngineInterface, bool) {
+	if s.registry == nil {
+		return s.gameEngine, gameID == ""
+	}
+	if gameID == "" {
+		gameID = DefaultGameID
+	}
+	return s.registry.Lookup(gameID)
+}
+
+func (s *APIServer) registerGameRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /games", s.handleListGames)
+	mux.HandleFunc("POST /games", s.handleCreateGame)
+	mux.HandleFunc("DELETE /games/{id}", s.handleRemoveGame)
+	mux.HandleFunc("POST /games/{id}/submit", s.handleSubmit)
+	mux.HandleFunc("GET /games/{id}/stats", s.handleStats)
+	mux.HandleFunc("GET /games/{id}/users/{user}", s.handleUser)
+}
+
+func (s *APIServer) handleListGames(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"games": s.registry.GameIDs(),
+	})
+}
+
+func (s *APIServer) handleCreateGame(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.ID == "" {
+		http.Error(w, "Request must be JSON with a non-empty id", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.registry.CreateGame(request.ID); err != nil {
+		http.Error(w, err.Error(), registryErrorStatus(err))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"game_id": request.ID,
+		"submit":  "/games/" + request.ID + "/submit",
+	})
+}
+
+func (s *APIServer) handleRemoveGame(w http.ResponseWriter, r *http.Request) {
+	if err := s.registry.RemoveGame(r.PathValue("id")); err != nil {
+		http.Error(w, err.Error(), registryErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// registryErrorStatus maps a GameRegistry error to its HTTP status.
+func registryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrGameNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrGameExists):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
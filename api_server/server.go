@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"io"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/clock"
 )
 
 type UserResponse struct {
@@ -14,20 +18,104 @@ type UserResponse struct {
 	Answer    string `json:"answer"`
 	IsCorrect bool   `json:"is_correct"`
 	Timestamp int64  `json:"timestamp"`
+	GameID    string `json:"game_id,omitempty"`
+	// Selections carries the picked options of a choice question or the
+	// items of an ordering question, in order.
+	Selections []string `json:"selections,omitempty"`
+	// TeamID names the team the user plays for in teams mode.
+	TeamID string `json:"team_id,omitempty"`
+}
+
+// SubmitStatus tells a client what happened to its submission.
+type SubmitStatus string
+
+const (
+	StatusWon  SubmitStatus = "won"
+	StatusLost SubmitStatus = "lost"
+	// StatusLate means the answer was correct but the podium was already
+	// full.
+	StatusLate SubmitStatus = "late"
+	// StatusClosed means the question had already closed, so the
+	// submission was not judged.
+	StatusClosed    SubmitStatus = "closed"
+	StatusExpired   SubmitStatus = "expired"
+	StatusPending   SubmitStatus = "pending"
+	StatusDuplicate SubmitStatus = "duplicate"
+	// StatusAlreadyWon means the answer was correct but the user already
+	// holds the podium rank in Rank, so it won nothing.
+	StatusAlreadyWon SubmitStatus = "already_won"
+	StatusCooldown   SubmitStatus = "cooldown"
+	StatusNotOpen    SubmitStatus = "not_open"
+	// StatusNotStarted means the question is scheduled to open later; retry
+	// after RetryAfter.
+	StatusNotStarted SubmitStatus = "not_started"
+	StatusInvalid    SubmitStatus = "invalid"
+	// StatusOverloaded means the engine's queue was full; retry after
+	// RetryAfter.
+	StatusOverloaded SubmitStatus = "overloaded"
+	// StatusDropped means the engine's queue was full and the submission was
+	// discarded.
+	StatusDropped SubmitStatus = "dropped"
+	// StatusPaused means the host has paused the question; retry once it
+	// resumes.
+	StatusPaused SubmitStatus = "paused"
+	// StatusEliminated means the user is out of an elimination game.
+	StatusEliminated SubmitStatus = "eliminated"
+	// StatusReset means the host reset the round before the submission was
+	// judged; it may be sent again once the round reopens.
+	StatusReset SubmitStatus = "reset"
+)
+
+// SubmitResult is the engine's definitive answer to a single submission.
+type SubmitResult struct {
+	Status SubmitStatus
+	// Rank is the podium rank the user holds, or 0.
+	Rank int
+	// RetryAfter is set with StatusCooldown, StatusOverloaded and
+	// StatusNotStarted to when the user may submit again.
+	RetryAfter time.Duration
+	// MatchRule names the answer-matching rule that accepted a correct
+	// answer, for resolving disputes.
+	MatchRule string
+	// Reason explains a StatusInvalid or StatusEliminated rejection.
+	Reason string
+	// Eliminated is set when this answer knocked the user out of an
+	// elimination game.
+	Eliminated bool
+}
+
+// Judged reports whether the submission was evaluated against the question,
+// as opposed to being turned away by the round state or attempt policy.
+func (r SubmitResult) Judged() bool {
+	switch r.Status {
+	case StatusWon, StatusLost, StatusLate, StatusPending, StatusAlreadyWon:
+		return true
+	}
+	return false
 }
 
 type APIServer struct {
 	port          string
 	gameEngine    GameEngineInterface
+	registry      GameRegistry
 	mu            sync.RWMutex
 	totalReceived int
 	startTime     time.Time
+	clock         clock.Clock
 }
 
 type GameEngineInterface interface {
-	ProcessResponse(response UserResponse) bool
+	// ProcessResponse returns an error if the engine can no longer accept
+	// submissions.
+	ProcessResponse(response UserResponse) (SubmitResult, error)
 	GetWinner() *UserResponse
 	Reset()
+	// StatsSnapshot returns a JSON-encodable point-in-time statistics
+	// snapshot.
+	StatsSnapshot() interface{}
+	// UserSnapshot returns a JSON-encodable record of one user's
+	// submissions, or false if the engine has none.
+	UserSnapshot(userID int) (interface{}, bool)
 }
 
 func NewAPIServer(port string, gameEngine GameEngineInterface) *APIServer {
@@ -35,14 +123,50 @@ func NewAPIServer(port string, gameEngine GameEngineInterface) *APIServer {
 		port:       port,
 		gameEngine: gameEngine,
 		startTime:  time.Now(),
+		clock:      clock.Real{},
+	}
+}
+
+// NewAPIServerWithRegistry creates a server hosting every game in registry.
+// Submissions to /submit without a game_id go to DefaultGameID.
+func NewAPIServerWithRegistry(port string, registry GameRegistry) *APIServer {
+	return &APIServer{
+		port:      port,
+		registry:  registry,
+		startTime: time.Now(),
+		clock:     clock.Real{},
 	}
 }
 
+// SetClock replaces the server's time source and restarts its uptime. Call
+// it before Start.
+func (s *APIServer) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clock = clock.Or(c)
+	s.startTime = s.clock.Now()
+}
+
+// Uptime returns how long the server has been running.
+func (s *APIServer) Uptime() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.clock.Since(s.startTime)
+}
+
 func (s *APIServer) Start() error {
-	http.HandleFunc("/submit", s.handleSubmit)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/submit", s.handleSubmit)
+	mux.HandleFunc("GET /stats", s.handleStats)
+	mux.HandleFunc("GET /users/{user}", s.handleUser)
+	if s.registry != nil {
+		s.registerGameRoutes(mux)
+	}
 
 	log.Printf("API Server starting on port %s (endpoint: /submit)", s.port)
-	return http.ListenAndServe(":"+s.port, nil)
+	return http.ListenAndServe(":"+s.port, mux)
 }
 
 func (s *APIServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
@@ -64,22 +188,55 @@ func (s *APIServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if id := r.PathValue("id"); id != "" {
+		response.GameID = id
+	}
+	engine, ok := s.resolveGame(response.GameID)
+	if !ok {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
 	s.mu.Lock()
 	s.totalReceived++
 	count := s.totalReceived
 	s.mu.Unlock()
 
-	isWinner := s.gameEngine.ProcessResponse(response)
+	submitted, err := engine.ProcessResponse(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
 
 	result := map[string]interface{}{
-		"received":  true,
-		"user_id":   response.UserID,
-		"is_winner": isWinner,
+		"received":       submitted.Judged(),
+		"status":         submitted.Status,
+		"user_id":        response.UserID,
+		"game_id":        response.GameID,
+		"is_winner":      submitted.Status == StatusWon,
+		"rank":           submitted.Rank,
+		"match_rule":     submitted.MatchRule,
+		"eliminated":     submitted.Eliminated || submitted.Status == StatusEliminated,
 		"response_count": count,
 	}
 
+	status := http.StatusOK
+	switch submitted.Status {
+	case StatusInvalid:
+		result["reason"] = submitted.Reason
+		status = http.StatusUnprocessableEntity
+	case StatusEliminated:
+		result["reason"] = submitted.Reason
+		status = http.StatusForbidden
+	case StatusOverloaded:
+		status = http.StatusServiceUnavailable
+	}
+
+	if submitted.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(submitted.RetryAfter.Seconds()))))
+	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(result)
 
 	if count%100 == 0 {
@@ -87,9 +244,38 @@ func (s *APIServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *APIServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	engine, ok := s.resolveGame(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, engine.StatsSnapshot())
+}
+
+func (s *APIServer) handleUser(w http.ResponseWriter, r *http.Request) {
+	engine, ok := s.resolveGame(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	userID, err := strconv.Atoi(r.PathValue("user"))
+	if err != nil {
+		http.Error(w, "User ID must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	record, ok := engine.UserSnapshot(userID)
+	if !ok {
+		http.Error(w, "No submissions received from this user", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
 
 func (s *APIServer) GetTotalResponses() int {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return s.totalReceived
-}
\ No newline at end of file
+}
@@ -0,0 +1,74 @@
+// Package clock abstracts the passage of time so that timing behaviour —
+// time to win, deadlines, periodic stats — can be driven by hand in tests.
+package clock
+
+import (
+	"time"
+)
+
+// Clock tells the time and schedules timers.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	// NewTimer returns a timer that sends the time on its channel once d
+	// has elapsed.
+	NewTimer(d time.Duration) Timer
+	// AfterFunc calls f once d has elapsed: in its own goroutine on Real,
+	// and from Advance on Fake. The returned timer has a nil channel.
+	AfterFunc(d time.Duration, f func()) Timer
+	// NewTicker returns a ticker that sends the time on its channel every
+	// d. It panics if d is not positive.
+	NewTicker(d time.Duration) Ticker
+	Sleep(d time.Duration)
+}
+
+// Timer is a single event scheduled on a Clock.
+type Timer interface {
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It reports whether the timer
+	// was still pending.
+	Stop() bool
+}
+
+// Ticker delivers ticks at a fixed interval on a Clock.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real is the system clock.
+type Real struct{}
+
+func (Real) Now() time.Time                  { return time.Now() }
+func (Real) Since(t time.Time) time.Duration { return time.Since(t) }
+func (Real) Sleep(d time.Duration)           { time.Sleep(d) }
+
+func (Real) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (Real) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct{ t *time.Timer }
+
+func (r realTimer) C() <-chan time.Time { return r.t.C }
+func (r realTimer) Stop() bool          { return r.t.Stop() }
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()               { r.t.Stop() }
+
+// Or returns c, or Real if c is nil.
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real{}
+	}
+	return c
+}
@@ -0,0 +1,199 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock that only moves when Advance or Set is called, so timers,
+// tickers and sleeps fire at exactly the times a test chooses. It is safe
+// for concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeTimer
+	// changed is closed and replaced whenever a waiter is added, for
+	// BlockUntil.
+	changed chan struct{}
+}
+
+// fakeTimer is a timer, an AfterFunc or, when period is set, a ticker.
+type fakeTimer struct {
+	fake   *Fake
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+	fn     func()
+}
+
+// NewFake returns a Fake clock reading now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, changed: make(chan struct{})}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// NewTimer returns a timer due d from now. A timer due now or earlier fires
+// on the next Advance, which may be Advance(0).
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.schedule(&fakeTimer{ch: make(chan time.Time, 1)}, d)
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.schedule(&fakeTimer{fn: fn}, d)
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return fakeTicker{f.schedule(&fakeTimer{period: d, ch: make(chan time.Time, 1)}, d)}
+}
+
+// Sleep blocks until another goroutine advances the clock by d.
+func (f *Fake) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-f.NewTimer(d).C()
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker that
+// falls due on the way in order, with the clock reading each one's due
+// time as it fires.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	target := f.now.Add(d)
+	f.mu.Unlock()
+
+	f.Set(target)
+}
+
+// Set moves the clock to t, as Advance does. Setting it backwards fires
+// nothing.
+func (f *Fake) Set(t time.Time) {
+	for {
+		f.mu.Lock()
+		next := f.due(t)
+		if next == nil {
+			if t.After(f.now) {
+				f.now = t
+			}
+			f.mu.Unlock()
+			return
+		}
+
+		if next.at.After(f.now) {
+			f.now = next.at
+		}
+		now := f.now
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+		} else {
+			f.remove(next)
+		}
+		f.mu.Unlock()
+
+		next.fire(now)
+	}
+}
+
+// Waiters returns the number of pending timers, tickers and sleeps.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil waits until at least n timers, tickers or sleeps are pending,
+// so a test can advance the clock knowing the code under test is waiting
+// on it.
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.mu.Lock()
+		pending, changed := len(f.waiters), f.changed
+		f.mu.Unlock()
+
+		if pending >= n {
+			return
+		}
+		<-changed
+	}
+}
+
+// schedule adds t to the waiters, due d from now.
+func (f *Fake) schedule(t *fakeTimer, d time.Duration) *fakeTimer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t.fake = f
+	t.at = f.now.Add(d)
+	f.waiters = append(f.waiters, t)
+	close(f.changed)
+	f.changed = make(chan struct{})
+	return t
+}
+
+// due returns the earliest waiter due at or before t, or nil. Must be
+// called with f.mu held.
+func (f *Fake) due(t time.Time) *fakeTimer {
+	var next *fakeTimer
+	for _, w := range f.waiters {
+		if w.at.After(t) {
+			continue
+		}
+		if next == nil || w.at.Before(next.at) {
+			next = w
+		}
+	}
+	return next
+}
+
+// remove drops t from the waiters and reports whether it was there. Must be
+// called with f.mu held.
+func (f *Fake) remove(t *fakeTimer) bool {
+	for i, w := range f.waiters {
+		if w == t {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fire delivers a tick at now without blocking, dropping it if the previous
+// one has not been received, as time.Ticker does.
+func (t *fakeTimer) fire(now time.Time) {
+	if t.fn != nil {
+		t.fn()
+		return
+	}
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTimer) Stop() bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+
+	return t.fake.remove(t)
+}
+
+type fakeTicker struct{ t *fakeTimer }
+
+func (t fakeTicker) C() <-chan time.Time { return t.t.ch }
+func (t fakeTicker) Stop()               { t.t.Stop() }
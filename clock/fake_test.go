@@ -0,0 +1,142 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeFiresTimersInOrder(t *testing.T) {
+	fake := NewFake(epoch)
+
+	var fired []time.Duration
+	for _, d := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+		fake.AfterFunc(d, func() { fired = append(fired, fake.Since(epoch)) })
+	}
+	timer := fake.NewTimer(1500 * time.Millisecond)
+
+	fake.Advance(5 * time.Second)
+
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if len(fired) != len(want) {
+		t.Fatalf("fired %v, want %v", fired, want)
+	}
+	for i := range want {
+		if fired[i] != want[i] {
+			t.Fatalf("fired %v, want %v", fired, want)
+		}
+	}
+	if at := <-timer.C(); at != epoch.Add(1500*time.Millisecond) {
+		t.Errorf("timer fired at %v, want its due time", at)
+	}
+	if got := fake.Since(epoch); got != 5*time.Second {
+		t.Errorf("clock reads %v after advancing, want 5s", got)
+	}
+	if n := fake.Waiters(); n != 0 {
+		t.Errorf("%d waiters left after every timer fired", n)
+	}
+}
+
+func TestFakeTimerDueNowFiresOnAdvanceZero(t *testing.T) {
+	fake := NewFake(epoch)
+	timer := fake.NewTimer(0)
+
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired before the clock was advanced")
+	default:
+	}
+
+	fake.Advance(0)
+	if at := <-timer.C(); !at.Equal(epoch) {
+		t.Errorf("timer fired at %v, want %v", at, epoch)
+	}
+}
+
+func TestFakeTickerRearms(t *testing.T) {
+	fake := NewFake(epoch)
+	ticker := fake.NewTicker(time.Second)
+
+	for i := 1; i <= 2; i++ {
+		fake.Advance(time.Second)
+		if at := <-ticker.C(); at != epoch.Add(time.Duration(i)*time.Second) {
+			t.Fatalf("tick %d at %v", i, at)
+		}
+	}
+
+	// Ticks nobody receives are dropped, as with time.Ticker.
+	fake.Advance(3 * time.Second)
+	if at := <-ticker.C(); at != epoch.Add(3*time.Second) {
+		t.Errorf("kept tick at %v, want the first undelivered one", at)
+	}
+	select {
+	case at := <-ticker.C():
+		t.Errorf("unexpected extra tick at %v", at)
+	default:
+	}
+	if n := fake.Waiters(); n != 1 {
+		t.Errorf("ticker not re-armed: %d waiters", n)
+	}
+}
+
+func TestFakeStop(t *testing.T) {
+	fake := NewFake(epoch)
+
+	called := false
+	timer := fake.AfterFunc(time.Second, func() { called = true })
+	ticker := fake.NewTicker(time.Second)
+
+	if !timer.Stop() {
+		t.Error("Stop of a pending timer returned false")
+	}
+	if timer.Stop() {
+		t.Error("second Stop returned true")
+	}
+	ticker.Stop()
+
+	fake.Advance(2 * time.Second)
+	if called {
+		t.Error("stopped AfterFunc ran")
+	}
+	select {
+	case <-ticker.C():
+		t.Error("stopped ticker ticked")
+	default:
+	}
+	if n := fake.Waiters(); n != 0 {
+		t.Errorf("%d waiters left after Stop", n)
+	}
+}
+
+func TestFakeAfterFuncRunsFromAdvance(t *testing.T) {
+	fake := NewFake(epoch)
+
+	var at time.Time
+	fake.AfterFunc(time.Minute, func() { at = fake.Now() })
+
+	fake.Advance(59 * time.Second)
+	if !at.IsZero() {
+		t.Fatal("AfterFunc ran early")
+	}
+	fake.Advance(time.Second)
+	if at != epoch.Add(time.Minute) {
+		t.Errorf("AfterFunc saw the clock at %v, want its due time", at)
+	}
+}
+
+func TestFakeSleep(t *testing.T) {
+	fake := NewFake(epoch)
+
+	done := make(chan time.Time)
+	go func() {
+		fake.Sleep(time.Second)
+		done <- fake.Now()
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	if at := <-done; at != epoch.Add(time.Second) {
+		t.Errorf("Sleep returned at %v", at)
+	}
+}
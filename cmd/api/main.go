@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -14,18 +15,36 @@ import (
 
 func main() {
 	var port string
+	var gameFile string
 	flag.StringVar(&port, "port", "8080", "Server port")
+	flag.StringVar(&gameFile, "game", "", "Game definition file (YAML or JSON)")
 	flag.Parse()
 
+	var def *game_engine.GameDefinition
+	var newConfig func() game_engine.Config
+	if gameFile != "" {
+		var err error
+		if def, err = game_engine.LoadGameDefinition(gameFile); err != nil {
+			log.Fatal(err)
+		}
+		newConfig = def.Config
+	}
+
 	fmt.Println("===========================================")
 	fmt.Println("       Game API Server Starting")
 	fmt.Println("===========================================")
 	fmt.Printf("Port: %s\n", port)
+	if def != nil {
+		fmt.Printf("Game: %s (%s, %d questions)\n", def.Name, gameFile, len(def.Questions))
+	}
 	fmt.Println()
 
-	engine := game_engine.NewGameEngine()
+	registry := game_engine.NewRegistry(context.Background(), newConfig)
+	if _, err := registry.CreateGame(api_server.DefaultGameID); err != nil {
+		log.Fatal("Failed to create game:", err)
+	}
 	
-	server := api_server.NewAPIServer(port, engine)
+	server := api_server.NewAPIServerWithRegistry(port, registry)
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -34,8 +53,15 @@ func main() {
 		<-sigChan
 		fmt.Println("\nShutting down server...")
 		
-		if winner := engine.GetWinner(); winner != nil {
-			fmt.Printf("\nFinal Winner: User %d with answer '%s'\n", winner.UserID, winner.Answer)
+		ctx, cancel := context.WithTimeout(context.Background(), game_engine.DefaultDrainTimeout)
+		summaries, err := registry.Shutdown(ctx)
+		cancel()
+		if err != nil {
+			fmt.Printf("Shutdown did not drain cleanly: %v\n", err)
+		}
+		
+		if stats := summaries[api_server.DefaultGameID].Stats; stats.HasWinner {
+			fmt.Printf("\nFinal Winner: User %d with answer '%s'\n", stats.WinnerUserID, stats.WinnerAnswer)
 		}
 		os.Exit(0)
 	}()
@@ -43,7 +69,12 @@ func main() {
 	fmt.Println("Server is ready to receive requests")
 	fmt.Printf("Endpoints:\n")
 	fmt.Printf("  POST /submit - Submit user responses\n")
+	fmt.Printf("  GET  /games  - List games\n")
+	fmt.Printf("  POST /games  - Create a game ({\"id\": \"room1\"})\n")
+	fmt.Printf("  POST /games/{id}/submit - Submit to a specific game\n")
+	fmt.Printf("  DELETE /games/{id}      - Tear down a game\n")
 	fmt.Printf("  GET  /stats  - View current statistics\n")
+	fmt.Printf("  GET  /users/{user} - View a user's submission history\n")
 	fmt.Printf("  POST /reset  - Reset the game\n")
 	fmt.Println("\nPress Ctrl+C to stop the server")
 	fmt.Println("-------------------------------------------")
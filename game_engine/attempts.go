@@ -0,0 +1,67 @@
+package game_engine
+
+import (
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// AttemptPolicy limits how often one user may submit during a round.
+type AttemptPolicy struct {
+	// MaxAttempts is the number of submissions a user gets per round. Zero
+	// means unlimited.
+	MaxAttempts int
+	// Cooldown is the minimum time between two submissions from one user.
+	Cooldown time.Duration
+}
+
+// SingleAttempt allows one submission per user per round.
+func SingleAttempt() AttemptPolicy {
+	return AttemptPolicy{MaxAttempts: 1}
+}
+
+// LimitedAttempts allows n submissions per user per round.
+func LimitedAttempts(n int) AttemptPolicy {
+	return AttemptPolicy{MaxAttempts: n}
+}
+
+// UnlimitedAttempts allows any number of submissions, at most one per
+// cooldown.
+func UnlimitedAttempts(cooldown time.Duration) AttemptPolicy {
+	return AttemptPolicy{Cooldown: cooldown}
+}
+
+// attemptRecord tracks one user's submissions in the current round.
+type attemptRecord struct {
+	count  int
+	lastAt time.Time
+}
+
+// admit applies the attempt policy to a submission received at now and
+// records it if allowed. Must be called with g.mu held.
+func (g *GameEngine) admit(userID int, now time.Time) (api_server.SubmitResult, bool) {
+	return g.attemptPolicy.admit(g.attempts, userID, now)
+}
+
+// admit applies the policy to a submission from userID received at now and
+// records it in attempts if allowed.
+func (p AttemptPolicy) admit(attempts map[int]*attemptRecord, userID int, now time.Time) (api_server.SubmitResult, bool) {
+	record := attempts[userID]
+	if record == nil {
+		record = &attemptRecord{}
+		attempts[userID] = record
+	}
+
+	if p.MaxAttempts > 0 && record.count >= p.MaxAttempts {
+		return api_server.SubmitResult{Status: api_server.StatusDuplicate}, false
+	}
+	if p.Cooldown > 0 && record.count > 0 {
+		if wait := record.lastAt.Add(p.Cooldown).Sub(now); wait > 0 {
+			return api_server.SubmitResult{Status: api_server.StatusCooldown, RetryAfter: wait}, false
+		}
+	}
+
+	record.count++
+	record.lastAt = now
+	return api_server.SubmitResult{}, true
+}
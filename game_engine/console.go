@@ -0,0 +1,96 @@
+package game_engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConsoleEvents are the event types ConsoleReporter prints.
+var ConsoleEvents = []EventType{
+	EventOpened, EventClosed, EventScored, EventReset, EventRoundStarted,
+	EventFinished, EventWinner, EventStatsTick, EventStopped, EventPaused,
+	EventResumed, EventCountdown, EventScheduleCancelled,
+}
+
+// ConsoleReporter prints engine events to stdout as banners and status lines.
+// It is subscribed automatically when Config.Console is set.
+func ConsoleReporter(event GameEvent) {
+	switch event.Type {
+	case EventWinner:
+		printPodiumEntry(event)
+	case EventReset:
+		printReset(event.Stats)
+	case EventStatsTick:
+		printLiveStats(event.Stats)
+	case EventStopped:
+		fmt.Printf("🛑 Game engine stopped after %d responses\n", event.Stats.TotalResponses)
+	case EventClosed:
+		printTransition(event)
+		if event.Stats != nil && event.Stats.NoWinner {
+			fmt.Printf("❌ Question %s closed with no winner\n", event.QuestionID)
+		}
+	case EventOpened, EventScored, EventRoundStarted, EventFinished, EventPaused, EventResumed,
+		EventCountdown, EventScheduleCancelled:
+		printTransition(event)
+	}
+}
+
+func printTransition(event GameEvent) {
+	if event.Reason != "" {
+		fmt.Printf("🔔 Question %s: %s -> %s (%s)\n", event.QuestionID, event.From, event.To, event.Reason)
+		return
+	}
+	fmt.Printf("🔔 Question %s: %s -> %s\n", event.QuestionID, event.From, event.To)
+}
+
+func printPodiumEntry(event GameEvent) {
+	entry := event.Podium
+	if entry.Rank > 1 {
+		fmt.Printf("🏅 Rank %d: User %d (%v)\n", entry.Rank, entry.Response.UserID, entry.TimeToWin)
+		return
+	}
+
+	fmt.Println("\n╔══════════════════════════════════════════╗")
+	fmt.Println("║           🎉 WINNER FOUND! 🎉           ║")
+	fmt.Println("╠══════════════════════════════════════════╣")
+	fmt.Printf("║ Winner ID:      %-25d║\n", entry.Response.UserID)
+	fmt.Printf("║ Answer:         %-25s║\n", entry.Response.Answer)
+	fmt.Printf("║ Time to win:    %-25v║\n", entry.TimeToWin)
+	fmt.Printf("║ Total responses: %-24d║\n", event.Stats.TotalResponses)
+	fmt.Printf("║ Correct answers: %-24d║\n", event.Stats.CorrectResponses)
+	fmt.Println("╚══════════════════════════════════════════╝")
+}
+
+func printReset(stats *Stats) {
+	fmt.Println("\n╔══════════════════════════════════════════╗")
+	fmt.Println("║           GAME ENGINE RESET              ║")
+	fmt.Println("╠══════════════════════════════════════════╣")
+
+	if stats.HasWinner {
+		fmt.Printf("║ Previous winner: User %-19d║\n", stats.WinnerUserID)
+	}
+
+	fmt.Printf("║ Total responses: %-24d║\n", stats.TotalResponses)
+	fmt.Printf("║ Correct responses: %-22d║\n", stats.CorrectResponses)
+
+	if stats.TotalResponses > 0 {
+		fmt.Printf("║ Success rate: %-28.1f%%║\n", stats.CorrectPercentage)
+	}
+
+	fmt.Println("╚══════════════════════════════════════════╝")
+}
+
+func printLiveStats(stats *Stats) {
+	if stats.StartsIn > 0 {
+		startsIn := time.Duration(stats.StartsIn * float64(time.Second))
+		fmt.Printf("⏳ Question %s opens in %v\n", stats.QuestionID, startsIn.Round(time.Second))
+		return
+	}
+	if stats.TotalResponses == 0 || stats.HasWinner || stats.GameDuration == 0 {
+		return
+	}
+
+	duration := time.Duration(stats.GameDuration * float64(time.Second))
+	fmt.Printf("📊 Live Stats | Total: %d | Correct: %d (%.1f%%) | Duration: %v\n",
+		stats.TotalResponses, stats.CorrectResponses, stats.CorrectPercentage, duration.Round(time.Second))
+}
@@ -0,0 +1,85 @@
+package game_engine
+
+import (
+	"time"
+)
+
+// Reasons a round was closed, reported on EventClosed and in RoundResult.
+const (
+	CloseReasonHost     = "host"
+	CloseReasonDeadline = "deadline"
+)
+
+// timeLimit returns how long the current question stays open, or 0 for no
+// limit. Must be called with g.mu held.
+func (g *GameEngine) timeLimit() time.Duration {
+	if limit := g.questions[g.current].TimeLimit; limit > 0 {
+		return limit
+	}
+	return g.defaultTimeLimit
+}
+
+// startDeadline arms the auto-close timer for a round opened at now. Must be
+// called with g.mu held.
+func (g *GameEngine) startDeadline(now time.Time) {
+	limit := g.timeLimit()
+	if limit <= 0 {
+		return
+	}
+
+	deadline := now.Add(limit)
+	g.deadline = &deadline
+	g.armDeadline(limit)
+}
+
+// armDeadline schedules the round to expire after d, superseding any earlier
+// timer. Must be called with g.mu held.
+func (g *GameEngine) armDeadline(d time.Duration) {
+	g.roundGen++
+	gen := g.roundGen
+	g.deadlineTimer = g.clock.AfterFunc(d, func() { g.expire(gen) })
+}
+
+// stopDeadline disarms the auto-close timer. The deadline itself is kept so
+// late submissions can still be told apart. Must be called with g.mu held.
+func (g *GameEngine) stopDeadline() {
+	if g.deadlineTimer != nil {
+		g.deadlineTimer.Stop()
+		g.deadlineTimer = nil
+	}
+}
+
+// expire closes the round armed as generation gen once its deadline passes.
+func (g *GameEngine) expire(gen uint64) {
+	g.flush()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.roundGen != gen || g.state != StateOpen {
+		return
+	}
+	g.closeRound(CloseReasonDeadline)
+}
+
+// pastDeadline reports whether t is after the current round's deadline. Must
+// be called with g.mu held.
+func (g *GameEngine) pastDeadline(t time.Time) bool {
+	return g.deadline != nil && t.After(*g.deadline)
+}
+
+// timeRemaining returns the countdown to the current round's deadline, frozen
+// while paused, or 0. Must be called with g.mu held.
+func (g *GameEngine) timeRemaining(now time.Time) time.Duration {
+	if g.deadline == nil {
+		return 0
+	}
+	switch g.state {
+	case StateOpen:
+		return max(g.deadline.Sub(now), 0)
+	case StatePaused:
+		return max(g.deadline.Sub(g.pausedAt()), 0)
+	default:
+		return 0
+	}
+}
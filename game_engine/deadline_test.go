@@ -0,0 +1,150 @@
+package game_engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+	"github.com/glitchdawg/game-engine-with-user/clock"
+)
+
+// newFakeClockEngine returns an open engine driven by a fake clock.
+func newFakeClockEngine(t *testing.T, cfg Config) (*GameEngine, *clock.Fake) {
+	t.Helper()
+
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg.Clock = fake
+	cfg.Console = false
+	engine := NewGameEngineWithConfig(context.Background(), cfg)
+	t.Cleanup(func() { engine.Stop(context.Background()) })
+
+	if err := engine.Open(); err != nil {
+		t.Fatal(err)
+	}
+	return engine, fake
+}
+
+func submit(t *testing.T, engine *GameEngine, userID int, answer string) api_server.SubmitResult {
+	t.Helper()
+
+	result, err := engine.ProcessResponse(api_server.UserResponse{UserID: userID, Answer: answer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestDeadlineClosesRound(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TimeLimit = 10 * time.Second
+	engine, fake := newFakeClockEngine(t, cfg)
+
+	fake.Advance(10*time.Second - time.Nanosecond)
+	if state := engine.State(); state != StateOpen {
+		t.Fatalf("state %s just before the deadline, want open", state)
+	}
+
+	fake.Advance(time.Nanosecond)
+	stats := engine.GetStats()
+	if stats.State != StateClosed.String() || stats.CloseReason != CloseReasonDeadline {
+		t.Fatalf("state %s (%s) at the deadline, want closed (deadline)", stats.State, stats.CloseReason)
+	}
+	if result := submit(t, engine, 1, "42"); result.Status != api_server.StatusExpired {
+		t.Errorf("submission after the deadline got %s, want expired", result.Status)
+	}
+}
+
+func TestPauseStopsDeadlineAndTimeToWin(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TimeLimit = 10 * time.Second
+	engine, fake := newFakeClockEngine(t, cfg)
+
+	fake.Advance(2 * time.Second)
+	submit(t, engine, 1, "wrong")
+	fake.Advance(time.Second)
+
+	if err := engine.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	if result := submit(t, engine, 2, "42"); result.Status != api_server.StatusPaused {
+		t.Fatalf("submission while paused got %s, want paused", result.Status)
+	}
+	fake.Advance(time.Minute)
+	if state := engine.State(); state != StatePaused {
+		t.Fatalf("state %s after a paused minute, want paused", state)
+	}
+	if err := engine.Resume(); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Advance(time.Second)
+	if result := submit(t, engine, 3, "42"); result.Status != api_server.StatusWon {
+		t.Fatalf("first correct answer got %s, want won", result.Status)
+	}
+
+	// Timed from the first response, less the paused minute.
+	stats := engine.GetStats()
+	if got := engine.GetPodium()[0].TimeToWin; got != 2*time.Second {
+		t.Errorf("time to win %v, want 2s", got)
+	}
+	if stats.TimeRemaining != 6 {
+		t.Errorf("%.3fs remaining, want 6s: the deadline should move back by the pause", stats.TimeRemaining)
+	}
+
+	fake.Advance(6 * time.Second)
+	if state := engine.State(); state != StateClosed {
+		t.Errorf("state %s at the extended deadline, want closed", state)
+	}
+}
+
+func TestFairnessOrdersByClientTime(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Fairness = FairnessConfig{Window: 100 * time.Millisecond, ClientTime: true, MaxClockSkew: 100 * time.Millisecond}
+	engine, fake := newFakeClockEngine(t, cfg)
+	fake.BlockUntil(2) // the stats and fairness tickers
+
+	now := fake.Now()
+	results := make(chan api_server.SubmitResult, 2)
+	send := func(userID int, sentAt time.Time) {
+		go func() {
+			result, _ := engine.ProcessResponse(api_server.UserResponse{UserID: userID, Answer: "42", Timestamp: sentAt.UnixNano()})
+			results <- result
+		}()
+	}
+
+	// User 2 arrives first but user 1 answered 50ms earlier.
+	send(2, now.Add(-10*time.Millisecond))
+	waitForBuffered(t, engine, 1)
+	send(1, now.Add(-60*time.Millisecond))
+	waitForBuffered(t, engine, 2)
+
+	// Step one fairness tick at a time: ticks the loop has not received yet
+	// are dropped, so a single large Advance would deliver only the first.
+	for engine.GetStats().FairnessBuffered > 0 {
+		if fake.Since(now) > time.Second {
+			t.Fatal("buffered submissions were never released")
+		}
+		fake.Advance(cfg.Fairness.Window / 4)
+		time.Sleep(time.Millisecond)
+	}
+	<-results
+	<-results
+
+	if winner := engine.GetWinner(); winner == nil || winner.UserID != 1 {
+		t.Fatalf("winner %+v, want user 1", winner)
+	}
+}
+
+// waitForBuffered waits until the fairness buffer holds n submissions.
+func waitForBuffered(t *testing.T, engine *GameEngine, n int64) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for engine.GetStats().FairnessBuffered < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("fairness buffer never reached %d submissions", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,471 @@
+package game_engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidDefinition is returned when a game definition cannot be parsed
+// or breaks a rule. The message names the file, line and field at fault.
+var ErrInvalidDefinition = errors.New("invalid game definition")
+
+// Winner strategies a game definition may name.
+const (
+	StrategyFirstCorrect      = "first_correct"
+	StrategyEarliestTimestamp = "earliest_timestamp"
+	StrategyRandomDraw        = "random_draw"
+	StrategyClosestGuess      = "closest_guess"
+)
+
+// scoringCurves maps the curve names a game definition may use to curves.
+var scoringCurves = map[string]DecayCurve{
+	"":            DecayNone,
+	"none":        DecayNone,
+	"linear":      DecayLinear,
+	"exponential": DecayExponential,
+	"stepped":     DecayStepped,
+}
+
+// GameDefinition describes a whole game: its questions and the rules it is
+// played by. It is written in YAML or JSON and loaded with
+// LoadGameDefinition; durations are strings such as "30s". Omitted settings
+// take their DefaultConfig values.
+type GameDefinition struct {
+	// Name is a title for the game, shown when it is loaded.
+	Name string `yaml:"name"`
+	// Rounds is the number of questions played. Zero plays them all.
+	Rounds int `yaml:"rounds"`
+	// TimeLimit closes each round this long after it opens, unless the
+	// question sets its own.
+	TimeLimit    time.Duration `yaml:"time_limit"`
+	PointsPerWin int           `yaml:"points_per_win"`
+	WinnerSlots  int           `yaml:"winner_slots"`
+	RankPoints   []int         `yaml:"rank_points"`
+	// Attempts limits submissions per user per round. Omitted means one.
+	Attempts    *AttemptsDefinition `yaml:"attempts"`
+	Strategy    StrategyDefinition  `yaml:"strategy"`
+	Scoring     ScoringDefinition   `yaml:"scoring"`
+	Elimination bool                `yaml:"elimination"`
+	// StartAt schedules the first round to open at this time, given in
+	// RFC 3339 form.
+	StartAt   time.Time            `yaml:"start_at"`
+	Questions []QuestionDefinition `yaml:"questions"`
+}
+
+// AttemptsDefinition is the attempt policy of a game definition.
+type AttemptsDefinition struct {
+	// Max is the number of submissions per round. Zero means unlimited.
+	Max      int           `yaml:"max"`
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+// StrategyDefinition names the winner strategy of a game definition and its
+// parameters.
+type StrategyDefinition struct {
+	// Type is one of the Strategy constants. Empty means first_correct.
+	Type string `yaml:"type"`
+	// Window is the earliest_timestamp collection window.
+	Window time.Duration `yaml:"window"`
+	// Deadline and Seed configure random_draw.
+	Deadline time.Duration `yaml:"deadline"`
+	Seed     int64         `yaml:"seed"`
+	// Target is the closest_guess target.
+	Target *float64 `yaml:"target"`
+}
+
+// ScoringDefinition is the speed scoring of a game definition.
+type ScoringDefinition struct {
+	// Curve is none, linear, exponential or stepped. Empty means none.
+	Curve     string        `yaml:"curve"`
+	MaxPoints int           `yaml:"max_points"`
+	MinPoints int           `yaml:"min_points"`
+	Duration  time.Duration `yaml:"duration"`
+	HalfLife  time.Duration `yaml:"half_life"`
+	Steps     []ScoreStep   `yaml:"steps"`
+}
+
+// QuestionDefinition is one question of a game definition.
+type QuestionDefinition struct {
+	ID     string       `yaml:"id"`
+	Prompt string       `yaml:"prompt"`
+	Type   QuestionType `yaml:"type"`
+	// Choices are the options of a choice question or the items of an
+	// ordering question.
+	Choices []string      `yaml:"choices"`
+	Range   *NumericRange `yaml:"range"`
+	// Answers is the answer key.
+	Answers   []string           `yaml:"answers"`
+	TimeLimit time.Duration      `yaml:"time_limit"`
+	Matching  MatchingDefinition `yaml:"matching"`
+}
+
+// MatchingDefinition is the answer matching of a question definition.
+type MatchingDefinition struct {
+	FoldCase         bool                `yaml:"fold_case"`
+	FoldSpace        bool                `yaml:"fold_space"`
+	Normalize        bool                `yaml:"normalize"`
+	FoldAccents      bool                `yaml:"fold_accents"`
+	Numeric          bool                `yaml:"numeric"`
+	NumericTolerance float64             `yaml:"numeric_tolerance"`
+	Synonyms         map[string][]string `yaml:"synonyms"`
+	MaxEditDistance  int                 `yaml:"max_edit_distance"`
+}
+
+// LoadGameDefinition reads and validates the game definition at path.
+func LoadGameDefinition(path string) (*GameDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGameDefinition(path, data)
+}
+
+// ParseGameDefinition parses and validates a YAML or JSON game definition.
+// name identifies the source in error messages. Unknown fields are errors,
+// and every rule that is broken is reported, not just the first.
+func ParseGameDefinition(name string, data []byte) (*GameDefinition, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, decodeError(name, err)
+	}
+
+	var def GameDefinition
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&def); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: %s: empty file", ErrInvalidDefinition, name)
+		}
+		return nil, decodeError(name, err)
+	}
+
+	c := definitionChecker{name: name, root: &root}
+	c.check(&def)
+	if len(c.errs) > 0 {
+		return nil, errors.Join(c.errs...)
+	}
+	return &def, nil
+}
+
+// decodeError rewrites a YAML syntax or type error as one error per problem,
+// located as name:line like the validation errors.
+func decodeError(name string, err error) error {
+	var typeErr *yaml.TypeError
+	problems := []string{strings.TrimPrefix(err.Error(), "yaml: ")}
+	if errors.As(err, &typeErr) {
+		problems = typeErr.Errors
+	}
+
+	errs := make([]error, len(problems))
+	for i, problem := range problems {
+		if rest, ok := strings.CutPrefix(problem, "line "); ok {
+			if line, msg, ok := strings.Cut(rest, ": "); ok {
+				errs[i] = fmt.Errorf("%w: %s:%s: %s", ErrInvalidDefinition, name, line, msg)
+				continue
+			}
+		}
+		errs[i] = fmt.Errorf("%w: %s: %s", ErrInvalidDefinition, name, problem)
+	}
+	return errors.Join(errs...)
+}
+
+// Config returns the engine configuration the definition describes, starting
+// from DefaultConfig. Each call builds a fresh winner strategy, so it can be
+// passed to NewRegistry for every game to get its own.
+func (d *GameDefinition) Config() Config {
+	cfg := DefaultConfig()
+	cfg.Rounds = d.Rounds
+	cfg.TimeLimit = d.TimeLimit
+	cfg.RankPoints = d.RankPoints
+	cfg.Elimination = d.Elimination
+	cfg.StartAt = d.StartAt
+	if d.PointsPerWin > 0 {
+		cfg.PointsPerWin = d.PointsPerWin
+	}
+	if d.WinnerSlots > 0 {
+		cfg.WinnerSlots = d.WinnerSlots
+	}
+	if d.Attempts != nil {
+		cfg.Attempts = AttemptPolicy{MaxAttempts: d.Attempts.Max, Cooldown: d.Attempts.Cooldown}
+	}
+
+	switch d.Strategy.Type {
+	case StrategyEarliestTimestamp:
+		cfg.Strategy = EarliestTimestamp(d.Strategy.Window)
+	case StrategyRandomDraw:
+		cfg.Strategy = RandomDraw(d.Strategy.Deadline, d.Strategy.Seed)
+	case StrategyClosestGuess:
+		cfg.Strategy = ClosestGuess(*d.Strategy.Target)
+	default:
+		cfg.Strategy = FirstCorrect()
+	}
+
+	cfg.Scoring = ScoringConfig{
+		Curve:     scoringCurves[d.Scoring.Curve],
+		MaxPoints: d.Scoring.MaxPoints,
+		MinPoints: d.Scoring.MinPoints,
+		Duration:  d.Scoring.Duration,
+		HalfLife:  d.Scoring.HalfLife,
+		Steps:     d.Scoring.Steps,
+	}
+
+	cfg.Questions = make(QuestionBank, len(d.Questions))
+	for i, q := range d.Questions {
+		cfg.Questions[i] = Question{
+			ID:              q.ID,
+			Prompt:          q.Prompt,
+			Type:            q.Type,
+			Choices:         q.Choices,
+			Range:           q.Range,
+			AcceptedAnswers: q.Answers,
+			TimeLimit:       q.TimeLimit,
+			Matching: MatchOptions{
+				FoldCase:         q.Matching.FoldCase,
+				FoldSpace:        q.Matching.FoldSpace,
+				Normalize:        q.Matching.Normalize,
+				FoldAccents:      q.Matching.FoldAccents,
+				Numeric:          q.Matching.Numeric,
+				NumericTolerance: q.Matching.NumericTolerance,
+				Synonyms:         q.Matching.Synonyms,
+				MaxEditDistance:  q.Matching.MaxEditDistance,
+			},
+		}
+	}
+	return cfg
+}
+
+// definitionChecker collects the rules a decoded definition breaks, locating
+// each in the source through its YAML node tree.
+type definitionChecker struct {
+	name string
+	root *yaml.Node
+	errs []error
+}
+
+// fail records a broken rule at path, a sequence of field names and
+// sequence indexes.
+func (c *definitionChecker) fail(path []any, format string, args ...any) {
+	field := ""
+	for _, p := range path {
+		switch p := p.(type) {
+		case int:
+			field += "[" + strconv.Itoa(p) + "]"
+		default:
+			if field != "" {
+				field += "."
+			}
+			field += fmt.Sprint(p)
+		}
+	}
+	msg := fmt.Sprintf(format, args...)
+	if line := c.line(path); line > 0 {
+		c.errs = append(c.errs, fmt.Errorf("%w: %s:%d: %s: %s", ErrInvalidDefinition, c.name, line, field, msg))
+	} else {
+		c.errs = append(c.errs, fmt.Errorf("%w: %s: %s: %s", ErrInvalidDefinition, c.name, field, msg))
+	}
+}
+
+// line returns the source line of the deepest node along path that exists,
+// or 0.
+func (c *definitionChecker) line(path []any) int {
+	node := c.root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line := node.Line
+	for _, p := range path {
+		var next *yaml.Node
+		switch p := p.(type) {
+		case int:
+			if node.Kind == yaml.SequenceNode && p < len(node.Content) {
+				next = node.Content[p]
+			}
+		case string:
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == p {
+						next = node.Content[i+1]
+						break
+					}
+				}
+			}
+		}
+		if next == nil {
+			break
+		}
+		node, line = next, next.Line
+	}
+	return line
+}
+
+// at returns path extended by more, without aliasing path's backing array.
+func at(path []any, more ...any) []any {
+	return append(slices.Clip(path), more...)
+}
+
+func (c *definitionChecker) check(d *GameDefinition) {
+	if len(d.Questions) == 0 {
+		c.fail([]any{"questions"}, "at least one question is required")
+	}
+	if d.Rounds < 0 || d.Rounds > len(d.Questions) {
+		c.fail([]any{"rounds"}, "must be between 0 and the number of questions (%d)", len(d.Questions))
+	}
+	if d.TimeLimit < 0 {
+		c.fail([]any{"time_limit"}, "must not be negative")
+	}
+	if d.PointsPerWin < 0 {
+		c.fail([]any{"points_per_win"}, "must not be negative")
+	}
+	if d.WinnerSlots < 0 {
+		c.fail([]any{"winner_slots"}, "must not be negative")
+	}
+	for i, points := range d.RankPoints {
+		if points < 0 {
+			c.fail([]any{"rank_points", i}, "must not be negative")
+		}
+	}
+	if a := d.Attempts; a != nil {
+		if a.Max < 0 {
+			c.fail([]any{"attempts", "max"}, "must not be negative")
+		}
+		if a.Cooldown < 0 {
+			c.fail([]any{"attempts", "cooldown"}, "must not be negative")
+		}
+	}
+	c.checkStrategy(d.Strategy)
+	c.checkScoring(d.Scoring)
+
+	seen := make(map[string]int, len(d.Questions))
+	for i, q := range d.Questions {
+		path := []any{"questions", i}
+		if q.ID == "" {
+			c.fail(path, "id is required")
+		} else if first, ok := seen[q.ID]; ok {
+			c.fail(at(path, "id"), "duplicate id %q, first used by questions[%d]", q.ID, first)
+		} else {
+			seen[q.ID] = i
+		}
+		c.checkQuestion(path, q)
+	}
+}
+
+func (c *definitionChecker) checkStrategy(s StrategyDefinition) {
+	path := []any{"strategy"}
+	switch s.Type {
+	case "", StrategyFirstCorrect, StrategyEarliestTimestamp, StrategyRandomDraw:
+	case StrategyClosestGuess:
+		if s.Target == nil {
+			c.fail(path, "target is required for %s", StrategyClosestGuess)
+		}
+	default:
+		c.fail(at(path, "type"), "unknown strategy %q; want %s, %s, %s or %s", s.Type,
+			StrategyFirstCorrect, StrategyEarliestTimestamp, StrategyRandomDraw, StrategyClosestGuess)
+	}
+	if s.Window < 0 {
+		c.fail(at(path, "window"), "must not be negative")
+	}
+	if s.Deadline < 0 {
+		c.fail(at(path, "deadline"), "must not be negative")
+	}
+}
+
+func (c *definitionChecker) checkScoring(s ScoringDefinition) {
+	path := []any{"scoring"}
+	curve, ok := scoringCurves[s.Curve]
+	if !ok {
+		c.fail(at(path, "curve"), "unknown curve %q; want none, linear, exponential or stepped", s.Curve)
+		return
+	}
+	if curve == DecayNone {
+		return
+	}
+	if s.MinPoints < 0 {
+		c.fail(at(path, "min_points"), "must not be negative")
+	}
+	if s.MaxPoints < s.MinPoints {
+		c.fail(at(path, "max_points"), "must be at least min_points (%d)", s.MinPoints)
+	}
+	if s.Duration < 0 {
+		c.fail(at(path, "duration"), "must not be negative")
+	}
+	switch curve {
+	case DecayExponential:
+		if s.HalfLife <= 0 {
+			c.fail(at(path, "half_life"), "must be positive for an exponential curve")
+		}
+	case DecayStepped:
+		if len(s.Steps) == 0 {
+			c.fail(at(path, "steps"), "at least one step is required for a stepped curve")
+		}
+		for i, step := range s.Steps {
+			if step.Within <= 0 {
+				c.fail(at(path, "steps", i, "within"), "must be positive")
+			} else if i > 0 && step.Within <= s.Steps[i-1].Within {
+				c.fail(at(path, "steps", i, "within"), "steps must be in increasing order of within")
+			}
+		}
+	}
+}
+
+func (c *definitionChecker) checkQuestion(path []any, q QuestionDefinition) {
+	if q.TimeLimit < 0 {
+		c.fail(at(path, "time_limit"), "must not be negative")
+	}
+	if q.Matching.NumericTolerance < 0 {
+		c.fail(at(path, "matching", "numeric_tolerance"), "must not be negative")
+	}
+	if q.Matching.MaxEditDistance < 0 {
+		c.fail(at(path, "matching", "max_edit_distance"), "must not be negative")
+	}
+	if q.Range != nil && q.Type != TypeNumeric {
+		c.fail(at(path, "range"), "only numeric questions take a range")
+	}
+
+	switch q.Type {
+	case "", TypeFreeText:
+		if len(q.Answers) == 0 {
+			c.fail(path, "answers are required")
+		}
+	case TypeSingleChoice, TypeMultiChoice, TypeOrdering:
+		if len(q.Choices) == 0 {
+			c.fail(path, "choices are required for a %s question", q.Type)
+			return
+		}
+		if len(q.Answers) == 0 {
+			c.fail(path, "answers are required")
+		}
+		for i, answer := range q.Answers {
+			if !slices.Contains(q.Choices, answer) {
+				c.fail(at(path, "answers", i), "%q is not one of the choices", answer)
+			}
+		}
+		if q.Type == TypeOrdering && len(q.Answers) > 0 &&
+			!slices.Equal(slices.Sorted(slices.Values(q.Answers)), slices.Sorted(slices.Values(q.Choices))) {
+			c.fail(at(path, "answers"), "must list every choice exactly once, in the correct order")
+		}
+	case TypeNumeric:
+		if len(q.Answers) == 0 && q.Range == nil {
+			c.fail(path, "answers or a range are required")
+		}
+		for i, answer := range q.Answers {
+			if _, ok := parseNumber(strings.TrimSpace(answer)); !ok {
+				c.fail(at(path, "answers", i), "%q is not a number", answer)
+			}
+		}
+		if q.Range != nil && q.Range.Min > q.Range.Max {
+			c.fail(at(path, "range"), "min %g is greater than max %g", q.Range.Min, q.Range.Max)
+		}
+	default:
+		c.fail(at(path, "type"), "unknown question type %q; want %s, %s, %s, %s or %s", q.Type,
+			TypeFreeText, TypeSingleChoice, TypeMultiChoice, TypeNumeric, TypeOrdering)
+	}
+}
@@ -0,0 +1,120 @@
+package game_engine
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// elimination tracks who is still in an elimination game. Users enter by
+// submitting during the first round; a wrong answer eliminates them at
+// once, and survivors who have not answered correctly by the time a round
+// is scored are eliminated with it.
+type elimination struct {
+	enabled bool
+	// alive holds the users still in the game.
+	alive map[int]bool
+	// eliminatedIn records the round each eliminated user went out in.
+	eliminatedIn map[int]int
+	// advanced holds the survivors who answered the current round correctly.
+	advanced map[int]bool
+}
+
+func newElimination(enabled bool) elimination {
+	return elimination{
+		enabled:      enabled,
+		alive:        make(map[int]bool),
+		eliminatedIn: make(map[int]int),
+		advanced:     make(map[int]bool),
+	}
+}
+
+// checkEliminated rejects a submission from a user who is out of the game or
+// arrives after the first round without having entered. Must be called with
+// g.mu held.
+func (g *GameEngine) checkEliminated(userID int) (api_server.SubmitResult, bool) {
+	if !g.elimination.enabled {
+		return api_server.SubmitResult{}, true
+	}
+	if round, ok := g.elimination.eliminatedIn[userID]; ok {
+		return api_server.SubmitResult{
+			Status: api_server.StatusEliminated,
+			Reason: fmt.Sprintf("eliminated in round %d", round),
+		}, false
+	}
+	if g.current > 0 && !g.elimination.alive[userID] {
+		return api_server.SubmitResult{
+			Status: api_server.StatusEliminated,
+			Reason: "entries closed after round 1",
+		}, false
+	}
+	return api_server.SubmitResult{}, true
+}
+
+// survive records a judged answer, eliminating the user if it was wrong. It
+// reports whether the user was eliminated. Must be called with g.mu held.
+func (g *GameEngine) survive(userID int, correct bool) bool {
+	if !g.elimination.enabled {
+		return false
+	}
+	if !correct {
+		delete(g.elimination.alive, userID)
+		g.elimination.eliminatedIn[userID] = g.current + 1
+		return true
+	}
+	g.elimination.alive[userID] = true
+	g.elimination.advanced[userID] = true
+	return false
+}
+
+// eliminateStragglers eliminates the survivors who did not answer the current
+// round correctly. Must be called with g.mu held.
+func (g *GameEngine) eliminateStragglers() {
+	if !g.elimination.enabled {
+		return
+	}
+	for userID := range g.elimination.alive {
+		if !g.elimination.advanced[userID] {
+			delete(g.elimination.alive, userID)
+			g.elimination.eliminatedIn[userID] = g.current + 1
+		}
+	}
+}
+
+// restoreRound brings back everyone eliminated in the current round, so it
+// can be replayed after Reset. Must be called with g.mu held.
+func (g *GameEngine) restoreRound() {
+	for userID, round := range g.elimination.eliminatedIn {
+		if round == g.current+1 {
+			delete(g.elimination.eliminatedIn, userID)
+			if g.current > 0 {
+				g.elimination.alive[userID] = true
+			}
+		}
+	}
+	if g.current == 0 {
+		// First-round entrants re-enter by submitting again.
+		clear(g.elimination.alive)
+	}
+	clear(g.elimination.advanced)
+}
+
+// survivorCount returns the number of users still in the game. Must be
+// called with g.mu held.
+func (g *GameEngine) survivorCount() int {
+	return len(g.elimination.alive)
+}
+
+// Survivors returns the IDs of the users still in an elimination game.
+func (g *GameEngine) Survivors() []int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	survivors := make([]int, 0, len(g.elimination.alive))
+	for userID := range g.elimination.alive {
+		survivors = append(survivors, userID)
+	}
+	slices.Sort(survivors)
+	return survivors
+}
@@ -1,12 +1,13 @@
 package game_engine
 
 import (
-	"fmt"
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
-	
+
 	"github.com/glitchdawg/game-engine-with-user/api_server"
+	"github.com/glitchdawg/game-engine-with-user/clock"
 )
 
 type GameEngine struct {
@@ -17,207 +18,534 @@ type GameEngine struct {
 	startTime        *time.Time
 	winnerFoundAt    *time.Time
 	eventChan        chan GameEvent
-	stopChan         chan bool
+	stopChan         chan struct{}
 	firstResponseAt  *time.Time
+	questions        QuestionBank
+	current          int
+	claimMismatches  int64
+	rejected         int64
+	state            GameState
+	openedAt         *time.Time
+	rounds           int
+	pointsPerWin     int
+	leaderboard      map[int]Standing
+	results          []RoundResult
+	podium           []PodiumEntry
+	winnerSlots      int
+	rankPoints       []int
+	fairness         FairnessConfig
+	fairQueue        fairQueue
+	buffered         int64
+	seq              uint64
+	flushChan        chan chan struct{}
+	attemptPolicy    AttemptPolicy
+	attempts         map[int]*attemptRecord
+	strategy         WinnerStrategy
+	stopOnce         sync.Once
+	defaultTimeLimit time.Duration
+	deadline         *time.Time
+	deadlineTimer    clock.Timer
+	roundGen         uint64
+	closeReason      string
+	scoring          ScoringConfig
+	roundPoints      map[int]speedScore
+	subs             subscribers
+	sendMu           sync.RWMutex
+	stopping         bool
+	stopReq          chan stopRequest
+	resetReq         chan resetRequest
+	summary          Summary
+	stopErr          error
+	queue            QueueConfig
+	overflows        int64
+	overflowRejected int64
+	overflowDropped  int64
+	spillMu          sync.Mutex
+	spill            []GameEvent
+	spillReady       chan struct{}
+	users            userRecords
+	teams            map[int]string
+	firstTeam        string
+	firstCorrectSeen bool
+	elimination      elimination
+	pauses           []pauseInterval
+	clock            clock.Clock
+	startsAt         *time.Time
+	startTimer       clock.Timer
+}
+
+// Config holds the settings a GameEngine is created with.
+type Config struct {
+	// Questions is the bank the engine validates answers against. Each
+	// tournament round serves the next question in order.
+	Questions QuestionBank
+	// Rounds is the number of rounds in the tournament. Zero, or a value
+	// larger than the question bank, plays every question once.
+	Rounds int
+	// PointsPerWin is awarded on the leaderboard to each podium finisher
+	// whose rank has no entry in RankPoints.
+	PointsPerWin int
+	// WinnerSlots is the number of podium places per round; the first
+	// WinnerSlots distinct users to answer correctly fill them in order.
+	WinnerSlots int
+	// RankPoints optionally sets the points for each podium rank, first
+	// place first.
+	RankPoints []int
+	// Fairness optionally orders submissions by a trusted timestamp.
+	Fairness FairnessConfig
+	// Attempts limits how often each user may submit per round.
+	Attempts AttemptPolicy
+	// Strategy decides who fills the podium. Nil means FirstCorrect.
+	Strategy WinnerStrategy
+	// TimeLimit closes each round automatically this long after it opens,
+	// unless the question sets its own. Zero means rounds stay open until
+	// closed by the host.
+	TimeLimit time.Duration
+	// Scoring optionally awards every correct answer points that decay with
+	// answer time, replacing podium points on the leaderboard.
+	Scoring ScoringConfig
+	// Console subscribes ConsoleReporter so events are printed to stdout.
+	Console bool
+	// DrainTimeout bounds the graceful stop when the engine's context is
+	// cancelled. Zero means DefaultDrainTimeout.
+	DrainTimeout time.Duration
+	// Queue sizes the event queue and sets what happens when it is full.
+	Queue QueueConfig
+	// Teams pre-assigns users to teams by user ID. Users not listed join
+	// the team named in their first submission with a team_id.
+	Teams map[int]string
+	// Elimination knocks users out of the game on a wrong answer, or on
+	// failing to answer a round correctly, until one survivor remains or
+	// the questions run out. Only first-round entrants may play.
+	Elimination bool
+	// Clock is the time source for timestamps, deadlines and the stats
+	// tick. Nil means clock.Real; tests can pass a clock.Fake.
+	Clock clock.Clock
+	// StartAt schedules the first round to open at this time, as
+	// ScheduleOpen does. Zero, or a time that has already passed, leaves it
+	// pending until the host opens it.
+	StartAt time.Time
 }
 
+// DefaultConfig returns the configuration used by NewGameEngine.
+func DefaultConfig() Config {
+	return Config{
+		Questions:    DefaultQuestionBank(),
+		PointsPerWin: 1,
+		WinnerSlots:  1,
+		Attempts:     SingleAttempt(),
+		Console:      true,
+	}
+}
+
+// GameEvent is a submission on its way to the event loop, and the typed
+// notification delivered to subscribers.
 type GameEvent struct {
-	Type     string
-	Response api_server.UserResponse
-	Time     time.Time
+	Type       EventType
+	QuestionID string
+	Round      int
+	Response   api_server.UserResponse
+	Time       time.Time
+	From       GameState
+	To         GameState
+	// Reason explains why a round was closed.
+	Reason string
+	// TrustedAt is the timestamp a response is ordered by in fairness mode.
+	TrustedAt time.Time
+	// Result is the verdict of an EventResponse.
+	Result api_server.SubmitResult
+	// Podium is the place awarded by an EventWinner.
+	Podium *PodiumEntry
+	// Stats is the engine snapshot attached to winner, closed, reset, stats
+	// tick and stopped events. For a reset it is taken before the round is
+	// cleared.
+	Stats *Stats
+
+	seq   uint64
+	reply chan api_server.SubmitResult
 }
 
-func NewGameEngine() *GameEngine {
+// NewGameEngine creates an engine with DefaultConfig. Cancelling ctx stops
+// it gracefully, as Stop does.
+func NewGameEngine(ctx context.Context) *GameEngine {
+	return NewGameEngineWithConfig(ctx, DefaultConfig())
+}
+
+// NewGameEngineWithConfig creates an engine serving the questions in cfg.
+// An empty question bank falls back to DefaultQuestionBank. Cancelling ctx
+// stops the engine gracefully within cfg.DrainTimeout.
+func NewGameEngineWithConfig(ctx context.Context, cfg Config) *GameEngine {
+	if len(cfg.Questions) == 0 {
+		cfg.Questions = DefaultQuestionBank()
+	}
+	if cfg.Rounds <= 0 || cfg.Rounds > len(cfg.Questions) {
+		cfg.Rounds = len(cfg.Questions)
+	}
+	if cfg.PointsPerWin <= 0 {
+		cfg.PointsPerWin = 1
+	}
+	if cfg.WinnerSlots <= 0 {
+		cfg.WinnerSlots = 1
+	}
+	if cfg.Strategy == nil {
+		cfg.Strategy = FirstCorrect()
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = DefaultDrainTimeout
+	}
+	cfg.Queue = cfg.Queue.withDefaults()
+	cfg.Fairness.MaxClockSkew = min(cfg.Fairness.MaxClockSkew, cfg.Fairness.Window)
+
 	g := &GameEngine{
-		eventChan: make(chan GameEvent, 1000),
-		stopChan:  make(chan bool),
+		eventChan:        make(chan GameEvent, cfg.Queue.Capacity),
+		stopChan:         make(chan struct{}),
+		stopReq:          make(chan stopRequest),
+		resetReq:         make(chan resetRequest),
+		questions:        cfg.Questions,
+		rounds:           cfg.Rounds,
+		pointsPerWin:     cfg.PointsPerWin,
+		leaderboard:      make(map[int]Standing),
+		winnerSlots:      cfg.WinnerSlots,
+		rankPoints:       cfg.RankPoints,
+		fairness:         cfg.Fairness,
+		flushChan:        make(chan chan struct{}),
+		attemptPolicy:    cfg.Attempts,
+		attempts:         make(map[int]*attemptRecord),
+		strategy:         cfg.Strategy,
+		defaultTimeLimit: cfg.TimeLimit,
+		scoring:          cfg.Scoring,
+		roundPoints:      make(map[int]speedScore),
+		subs:             subscribers{chans: make(map[int]subscriber)},
+		queue:            cfg.Queue,
+		spillReady:       make(chan struct{}, 1),
+		users:            make(userRecords),
+		teams:            make(map[int]string, len(cfg.Teams)),
+		elimination:      newElimination(cfg.Elimination),
+		clock:            clock.Or(cfg.Clock),
+	}
+	for userID, teamID := range cfg.Teams {
+		g.teams[userID] = teamID
+	}
+
+	if cfg.Console {
+		g.SubscribeFunc(ConsoleReporter, ConsoleEvents...)
 	}
-	
+
 	go g.processEvents()
-	go g.printMetrics()
-	
+	go g.tickStats(5 * time.Second)
+	go g.stopWhenDone(ctx, cfg.DrainTimeout)
+
+	if cfg.StartAt.After(g.clock.Now()) {
+		// A new engine is always pending, so this can only fail on a bug.
+		if err := g.ScheduleOpen(cfg.StartAt); err != nil {
+			panic(err)
+		}
+	}
+
 	return g
 }
 
 func (g *GameEngine) processEvents() {
+	var tick <-chan time.Time
+	if g.fairness.Enabled() {
+		ticker := g.clock.NewTicker(max(g.fairness.Window/4, time.Millisecond))
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
 	for {
 		select {
 		case event := <-g.eventChan:
-			g.handleEvent(event)
-		case <-g.stopChan:
+			g.dispatch(event)
+		case <-g.spillReady:
+			g.unspill()
+		case now := <-tick:
+			g.release(now.Add(-g.fairness.Window))
+		case done := <-g.flushChan:
+			g.drain()
+			close(done)
+		case req := <-g.resetReq:
+			g.mu.Lock()
+			g.discard()
+			req.reset()
+			g.mu.Unlock()
+			close(req.done)
+		case req := <-g.stopReq:
+			req.done <- g.drainForStop(req.ctx)
 			return
 		}
 	}
 }
 
+// dispatch judges an event immediately, or buffers it in fairness mode.
+func (g *GameEngine) dispatch(event GameEvent) {
+	if g.fairness.Enabled() {
+		g.buffer(event)
+		return
+	}
+	g.handleEvent(event)
+}
+
 func (g *GameEngine) handleEvent(event GameEvent) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	event.Response.TeamID = g.joinTeam(event.Response)
+	user := g.users.received(event.Response, event.Time)
+	result := g.judge(&event, user)
+	user.answered(result, event.Time, g.clock.Now())
+	event.reply <- result
+
+	event.Result = result
+	event.reply = nil
+	g.emit(event)
+}
+
+// judge decides the outcome of a single submission and counts it on the
+// submitting user's record. It replaces the client's is_correct claim in
+// event with the engine's verdict, false if the submission was not judged,
+// so subscribers never see the claim. Must be called with g.mu held, and
+// only from the event loop.
+func (g *GameEngine) judge(event *GameEvent, user *userRecord) api_server.SubmitResult {
+	if result, ok := g.admission(*event); !ok {
+		event.Response.IsCorrect = false
+		atomic.AddInt64(&g.rejected, 1)
+		user.Rejected++
+		return result
+	}
+	user.Attempts++
+
+	// The client's is_correct claim is only a cross-check; the engine decides.
+	match := g.questions[g.current].Evaluate(event.Response)
+	correct := match.Matched
+	if correct != event.Response.IsCorrect {
+		atomic.AddInt64(&g.claimMismatches, 1)
+	}
+	event.Response.IsCorrect = correct
+
 	atomic.AddInt64(&g.totalResponses, 1)
-	
-	if event.Response.IsCorrect {
+	if correct {
 		atomic.AddInt64(&g.correctResponses, 1)
+		user.Correct++
+		// A podium finisher's repeat answer counts as correct but wins
+		// nothing more.
+		if rank := g.rankOf(event.Response.UserID); rank > 0 {
+			return api_server.SubmitResult{Status: api_server.StatusAlreadyWon, Rank: rank, MatchRule: string(match.Rule)}
+		}
+		if !g.firstCorrectSeen {
+			g.firstCorrectSeen = true
+			g.firstTeam = event.Response.TeamID
+		}
 	}
-	
-	g.mu.Lock()
-	defer g.mu.Unlock()
-	
+	eliminated := g.survive(event.Response.UserID, correct)
+
+	at := event.TrustedAt
+	if g.openedAt != nil && at.Before(*g.openedAt) {
+		at = *g.openedAt
+	}
+
 	// Set start time on first response
 	if g.firstResponseAt == nil {
-		now := time.Now()
-		g.firstResponseAt = &now
-		g.startTime = &now
-	}
-	
-	if g.winner == nil && event.Response.IsCorrect {
-		g.winner = &event.Response
-		now := time.Now()
-		g.winnerFoundAt = &now
-		
-		var timeTaken time.Duration
-		if g.startTime != nil {
-			timeTaken = now.Sub(*g.startTime)
-		} else {
-			timeTaken = 0
+		g.firstResponseAt = &at
+	}
+	if g.startTime == nil {
+		g.startTime = &at
+	}
+
+	var elapsed time.Duration
+	if g.openedAt != nil {
+		elapsed = g.activeBetween(*g.openedAt, at)
+	}
+
+	if correct {
+		g.awardSpeedPoints(event.Response.UserID, elapsed)
+	}
+
+	submission := Submission{
+		Response:   event.Response,
+		Correct:    correct,
+		At:         at,
+		Elapsed:    elapsed,
+		ReceivedAt: event.Time,
+		MatchRule:  match.Rule,
+	}
+	switch g.strategy.Offer(submission) {
+	case VerdictWin:
+		wonAt := g.clock.Now()
+		if g.fairness.Enabled() {
+			wonAt = at
 		}
-		
-		fmt.Println("\n╔══════════════════════════════════════════╗")
-		fmt.Println("║           🎉 WINNER FOUND! 🎉           ║")
-		fmt.Println("╠══════════════════════════════════════════╣")
-		fmt.Printf("║ Winner ID:      %-25d║\n", event.Response.UserID)
-		fmt.Printf("║ Answer:         %-25s║\n", event.Response.Answer)
-		fmt.Printf("║ Time to win:    %-25v║\n", timeTaken)
-		fmt.Printf("║ Total responses: %-24d║\n", atomic.LoadInt64(&g.totalResponses))
-		fmt.Printf("║ Correct answers: %-24d║\n", atomic.LoadInt64(&g.correctResponses))
-		fmt.Println("╚══════════════════════════════════════════╝")
-	}
-}
-
-func (g *GameEngine) printMetrics() {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-	
-	for {
-		select {
-		case <-ticker.C:
-			total := atomic.LoadInt64(&g.totalResponses)
-			correct := atomic.LoadInt64(&g.correctResponses)
-			
-			if total > 0 {
-				g.mu.RLock()
-				hasWinner := g.winner != nil
-				g.mu.RUnlock()
-				
-				if !hasWinner && g.startTime != nil {
-					percentage := float64(correct) / float64(total) * 100
-					fmt.Printf("📊 Live Stats | Total: %d | Correct: %d (%.1f%%) | Duration: %v\n", 
-						total, correct, percentage, time.Since(*g.startTime).Round(time.Second))
-				}
-			}
-		case <-g.stopChan:
-			return
+		if rank := g.place(submission, wonAt); rank > 0 {
+			return api_server.SubmitResult{Status: api_server.StatusWon, Rank: rank, MatchRule: string(match.Rule)}
 		}
+		return api_server.SubmitResult{Status: api_server.StatusLate, MatchRule: string(match.Rule)}
+	case VerdictPending:
+		return api_server.SubmitResult{Status: api_server.StatusPending, MatchRule: string(match.Rule), Eliminated: eliminated}
+	default:
+		return api_server.SubmitResult{Status: api_server.StatusLost, MatchRule: string(match.Rule), Eliminated: eliminated}
 	}
 }
 
-func (g *GameEngine) ProcessResponse(response api_server.UserResponse) bool {
+// ProcessResponse submits a response and waits for the event loop to judge
+// it, so the result is the definitive outcome for this submission: won (with
+// its podium rank), already won by an earlier answer, lost, late, pending a
+// deferred strategy's decision, or rejected by the round state, attempt
+// policy or a full queue. Once the engine is stopping it returns ErrStopped.
+func (g *GameEngine) ProcessResponse(response api_server.UserResponse) (api_server.SubmitResult, error) {
+	g.sendMu.RLock()
+	if g.stopping {
+		g.sendMu.RUnlock()
+		return api_server.SubmitResult{}, ErrStopped
+	}
+
+	now := g.clock.Now()
 	event := GameEvent{
-		Type:     "response",
-		Response: response,
-		Time:     time.Now(),
+		Type:      EventResponse,
+		Response:  response,
+		Time:      now,
+		TrustedAt: g.fairness.trustedTime(response.Timestamp, now),
+		seq:       atomic.AddUint64(&g.seq, 1),
+		reply:     make(chan api_server.SubmitResult, 1),
 	}
-	
+
+	result, ok := g.enqueue(event)
+	g.sendMu.RUnlock()
+	if !ok {
+		return result, nil
+	}
+
 	select {
-	case g.eventChan <- event:
+	case result := <-event.reply:
+		return result, nil
+	case <-g.stopChan:
+		return api_server.SubmitResult{}, ErrStopped
+	}
+}
+
+// admission applies the round state, elimination, the question type and the
+// attempt policy to a submission. Invalid payloads do not use up an attempt.
+// Must be called with g.mu held.
+func (g *GameEngine) admission(event GameEvent) (api_server.SubmitResult, bool) {
+	switch g.state {
+	case StateOpen:
+		if g.pastDeadline(event.Time) {
+			return api_server.SubmitResult{Status: api_server.StatusExpired}, false
+		}
+		if result, ok := g.checkEliminated(event.Response.UserID); !ok {
+			return result, false
+		}
+		if err := g.questions[g.current].Validate(event.Response); err != nil {
+			return api_server.SubmitResult{Status: api_server.StatusInvalid, Reason: err.Error()}, false
+		}
+		return g.admit(event.Response.UserID, event.Time)
+	case StatePending:
+		return api_server.SubmitResult{Status: api_server.StatusNotOpen}, false
+	case StateCountdown:
+		return g.notStarted(event.Time), false
+	case StatePaused:
+		return api_server.SubmitResult{Status: api_server.StatusPaused}, false
 	default:
-		fmt.Println("Warning: Event channel full, processing synchronously")
-		g.handleEvent(event)
+		if g.closeReason == CloseReasonDeadline {
+			return api_server.SubmitResult{Status: api_server.StatusExpired}, false
+		}
+		return api_server.SubmitResult{Status: api_server.StatusClosed}, false
 	}
-	
+}
+
+// CurrentQuestion returns the question submissions are being checked against.
+func (g *GameEngine) CurrentQuestion() Question {
 	g.mu.RLock()
-	isWinner := g.winner != nil && g.winner.UserID == response.UserID
-	g.mu.RUnlock()
-	
-	return isWinner
+	defer g.mu.RUnlock()
+
+	return g.questions[g.current]
 }
 
 func (g *GameEngine) GetWinner() *api_server.UserResponse {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	
+
 	if g.winner == nil {
 		return nil
 	}
-	
+
 	winnerCopy := *g.winner
 	return &winnerCopy
 }
 
+// Reset returns the current round to StatePending from any state,
+// discarding its winner, counters and any points it awarded so it can be
+// played again. The rest of the leaderboard is kept. Submissions not yet
+// judged are turned away with StatusReset.
 func (g *GameEngine) Reset() {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-	
-	fmt.Println("\n╔══════════════════════════════════════════╗")
-	fmt.Println("║           GAME ENGINE RESET              ║")
-	fmt.Println("╠══════════════════════════════════════════╣")
-	
-	if g.winner != nil {
-		fmt.Printf("║ Previous winner: User %-19d║\n", g.winner.UserID)
-	}
-	
-	total := atomic.LoadInt64(&g.totalResponses)
-	correct := atomic.LoadInt64(&g.correctResponses)
-	
-	fmt.Printf("║ Total responses: %-24d║\n", total)
-	fmt.Printf("║ Correct responses: %-22d║\n", correct)
-	
-	if total > 0 {
-		percentage := float64(correct) / float64(total) * 100
-		fmt.Printf("║ Success rate: %-28.1f%%║\n", percentage)
-	}
-	
-	fmt.Println("╚══════════════════════════════════════════╝")
-	
+	g.resetRound(g.reset)
+}
+
+// reset does the work of Reset. Must be called with g.mu held.
+func (g *GameEngine) reset() {
+	stats := g.snapshot(g.clock.Now())
+
+	// A finished tournament ends on a scored round, so its points go too.
+	if g.state == StateScored || g.state == StateFinished {
+		g.unscoreRound()
+	}
+	if g.elimination.enabled {
+		g.restoreRound()
+	}
+	g.clearRound()
+
+	from := g.state
+	g.state = StatePending
+	g.emit(GameEvent{Type: EventReset, From: from, To: StatePending, Time: g.clock.Now(), Stats: &stats})
+}
+
+// resetRequest asks the event loop to discard every submission not yet judged
+// and then run reset with g.mu held.
+type resetRequest struct {
+	reset func()
+	done  chan struct{}
+}
+
+// resetRound runs reset, which must be called with g.mu held, in the event
+// loop straight after discarding every submission not yet judged, so none of
+// them reaches the next round. Once the engine has stopped nothing is left to
+// discard and reset runs here. It must not be called from the event loop or
+// with g.mu held.
+func (g *GameEngine) resetRound(reset func()) {
+	req := resetRequest{reset: reset, done: make(chan struct{})}
+	select {
+	case g.resetReq <- req:
+		<-req.done
+	case <-g.stopChan:
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		reset()
+	}
+}
+
+// clearRound discards the per-round winner, counters and timestamps. Must be
+// called with g.mu held.
+func (g *GameEngine) clearRound() {
 	g.winner = nil
+	g.podium = nil
+	g.attempts = make(map[int]*attemptRecord)
+	g.roundPoints = make(map[int]speedScore)
+	g.strategy.Reset()
 	atomic.StoreInt64(&g.totalResponses, 0)
 	atomic.StoreInt64(&g.correctResponses, 0)
+	atomic.StoreInt64(&g.claimMismatches, 0)
+	atomic.StoreInt64(&g.rejected, 0)
 	g.startTime = nil
 	g.winnerFoundAt = nil
 	g.firstResponseAt = nil
+	g.openedAt = nil
+	g.stopDeadline()
+	g.deadline = nil
+	g.closeReason = ""
+	g.firstTeam = ""
+	g.firstCorrectSeen = false
+	clear(g.elimination.advanced)
+	g.pauses = nil
+	g.stopSchedule()
+	g.startsAt = nil
+	g.roundGen++
 }
-
-func (g *GameEngine) GetStats() map[string]interface{} {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
-	
-	total := atomic.LoadInt64(&g.totalResponses)
-	correct := atomic.LoadInt64(&g.correctResponses)
-	
-	stats := map[string]interface{}{
-		"total_responses":   total,
-		"correct_responses": correct,
-		"has_winner":        g.winner != nil,
-	}
-	
-	if g.startTime != nil {
-		stats["game_duration"] = time.Since(*g.startTime).Seconds()
-	} else {
-		stats["game_duration"] = 0.0
-	}
-	
-	if g.winner != nil {
-		stats["winner_user_id"] = g.winner.UserID
-		stats["winner_answer"] = g.winner.Answer
-		if g.winnerFoundAt != nil && g.startTime != nil {
-			stats["time_to_win"] = g.winnerFoundAt.Sub(*g.startTime).Seconds()
-		}
-	}
-	
-	if total > 0 {
-		stats["correct_percentage"] = float64(correct) / float64(total) * 100
-	}
-	
-	return stats
-}
-
-func (g *GameEngine) Shutdown() {
-	close(g.stopChan)
-}
\ No newline at end of file
@@ -0,0 +1,78 @@
+package game_engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+func TestLateAnswersAreJudgedButClosedRoundsAreNot(t *testing.T) {
+	engine, _ := newFakeClockEngine(t, DefaultConfig())
+
+	submit(t, engine, 1, "42")
+	late := submit(t, engine, 2, "42")
+	if late.Status != api_server.StatusLate || !late.Judged() {
+		t.Errorf("correct answer after the podium filled got %s (judged %t), want judged late", late.Status, late.Judged())
+	}
+
+	if err := engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	closed := submit(t, engine, 3, "42")
+	if closed.Status != api_server.StatusClosed || closed.Judged() {
+		t.Errorf("submission to a closed round got %s (judged %t), want unjudged closed", closed.Status, closed.Judged())
+	}
+	if total := engine.GetStats().TotalResponses; total != 2 {
+		t.Errorf("%d responses counted, want the 2 judged ones", total)
+	}
+}
+
+func TestRepeatAnswerFromPodiumCountsAsCorrect(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Attempts = UnlimitedAttempts(0)
+	engine, _ := newFakeClockEngine(t, cfg)
+
+	submit(t, engine, 1, "42")
+	repeat := submit(t, engine, 1, "42")
+	if repeat.Status != api_server.StatusAlreadyWon || repeat.Rank != 1 {
+		t.Fatalf("repeat answer got %s rank %d, want already_won rank 1", repeat.Status, repeat.Rank)
+	}
+
+	stats := engine.GetStats()
+	if stats.TotalResponses != 2 || stats.CorrectResponses != 2 {
+		t.Errorf("%d of %d responses correct, want 2 of 2", stats.CorrectResponses, stats.TotalResponses)
+	}
+	if user, _ := engine.GetUserRecord(1); user.Attempts != 2 || user.Correct != 2 {
+		t.Errorf("user 1 has %d of %d attempts correct, want 2 of 2", user.Correct, user.Attempts)
+	}
+	if len(engine.GetPodium()) != 1 {
+		t.Errorf("podium %+v, want user 1 once", engine.GetPodium())
+	}
+}
+
+func TestResetTurnsAwayBufferedSubmissions(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Fairness = FairnessConfig{Window: 100 * time.Millisecond}
+	engine, fake := newFakeClockEngine(t, cfg)
+
+	results := make(chan api_server.SubmitResult, 1)
+	go func() { results <- submit(t, engine, 1, "42") }()
+	waitForBuffered(t, engine, 1)
+
+	engine.Reset()
+	if result := <-results; result.Status != api_server.StatusReset {
+		t.Errorf("submission buffered across a reset got %s, want reset", result.Status)
+	}
+
+	if err := engine.Open(); err != nil {
+		t.Fatal(err)
+	}
+	fake.Advance(time.Second)
+	if winner := engine.GetWinner(); winner != nil {
+		t.Errorf("answer from before the reset won the new round: %+v", winner)
+	}
+	if stats := engine.GetStats(); stats.FairnessBuffered != 0 || stats.TotalResponses != 0 {
+		t.Errorf("%d buffered and %d judged after the reset, want none", stats.FairnessBuffered, stats.TotalResponses)
+	}
+}
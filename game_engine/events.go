@@ -0,0 +1,143 @@
+package game_engine
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultSubscriberBuffer is the channel size SubscribeFunc uses.
+const defaultSubscriberBuffer = 256
+
+// subscribers fans engine events out to registered channels.
+type subscribers struct {
+	mu      sync.RWMutex
+	next    int
+	chans   map[int]subscriber
+	dropped int64
+	closed  bool
+}
+
+type subscriber struct {
+	ch    chan GameEvent
+	types []EventType
+}
+
+// wants reports whether the subscriber asked for events of type t.
+func (s subscriber) wants(t EventType) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, t)
+}
+
+// Subscribe returns a channel receiving the events the engine emits from now
+// on, and a function that unsubscribes and closes it. If types are given,
+// only events of those types are delivered. Delivery never blocks the
+// engine: events are dropped, and counted in Stats, while the channel's
+// buffer is full. Channels are closed when the engine stops; subscribing to
+// a stopped engine returns a closed channel.
+func (g *GameEngine) Subscribe(buffer int, types ...EventType) (<-chan GameEvent, func()) {
+	ch := make(chan GameEvent, buffer)
+
+	g.subs.mu.Lock()
+	if g.subs.closed {
+		g.subs.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	id := g.subs.next
+	g.subs.next++
+	g.subs.chans[id] = subscriber{ch: ch, types: types}
+	g.subs.mu.Unlock()
+
+	return ch, func() {
+		g.subs.mu.Lock()
+		defer g.subs.mu.Unlock()
+
+		if _, ok := g.subs.chans[id]; ok {
+			delete(g.subs.chans, id)
+			close(ch)
+		}
+	}
+}
+
+// closeSubscribers closes every subscriber channel and refuses new ones.
+func (g *GameEngine) closeSubscribers() {
+	g.subs.mu.Lock()
+	defer g.subs.mu.Unlock()
+
+	for id, sub := range g.subs.chans {
+		delete(g.subs.chans, id)
+		close(sub.ch)
+	}
+	g.subs.closed = true
+}
+
+// SubscribeFunc calls fn with the events the engine emits, in order, on a
+// dedicated goroutine. The returned function unsubscribes.
+func (g *GameEngine) SubscribeFunc(fn func(GameEvent), types ...EventType) func() {
+	events, unsubscribe := g.Subscribe(defaultSubscriberBuffer, types...)
+	go func() {
+		for event := range events {
+			fn(event)
+		}
+	}()
+	return unsubscribe
+}
+
+// emit delivers an event to every subscriber, filling in its question, round
+// and time. Must be called with g.mu held, for reading at least.
+func (g *GameEngine) emit(event GameEvent) {
+	g.subs.mu.RLock()
+	defer g.subs.mu.RUnlock()
+
+	if len(g.subs.chans) == 0 {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = g.clock.Now()
+	}
+	event.QuestionID = g.questions[g.current].ID
+	event.Round = g.current + 1
+
+	for _, sub := range g.subs.chans {
+		if !sub.wants(event.Type) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			atomic.AddInt64(&g.subs.dropped, 1)
+		}
+	}
+}
+
+// hasSubscribers reports whether any subscriber is registered.
+func (g *GameEngine) hasSubscribers() bool {
+	g.subs.mu.RLock()
+	defer g.subs.mu.RUnlock()
+
+	return len(g.subs.chans) > 0
+}
+
+// tickStats emits an EventStatsTick snapshot every interval until the engine
+// stops.
+func (g *GameEngine) tickStats(interval time.Duration) {
+	ticker := g.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C():
+			if !g.hasSubscribers() {
+				continue
+			}
+			g.mu.RLock()
+			stats := g.snapshot(now)
+			g.emit(GameEvent{Type: EventStatsTick, Time: now, Stats: &stats})
+			g.mu.RUnlock()
+		case <-g.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package game_engine
+
+import (
+	"container/heap"
+	"sync/atomic"
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// FairnessConfig enables fairness mode: instead of judging submissions in
+// the order they reach the event loop, the engine holds them for Window and
+// judges them in order of a trusted timestamp.
+type FairnessConfig struct {
+	// Window is how long a submission is buffered before it is judged. Zero
+	// disables fairness mode.
+	Window time.Duration
+	// ClientTime orders submissions by UserResponse.Timestamp instead of
+	// server receipt time. The client time is clamped so it is never later
+	// than receipt and never earlier than receipt minus MaxClockSkew.
+	ClientTime bool
+	// MaxClockSkew bounds how far before receipt a client timestamp may be
+	// trusted. It is capped at Window: a submission trusted further back
+	// could sort behind submissions already released.
+	MaxClockSkew time.Duration
+}
+
+// Enabled reports whether fairness mode is on.
+func (f FairnessConfig) Enabled() bool {
+	return f.Window > 0
+}
+
+// trustedTime returns the timestamp a submission received at receivedAt is
+// ordered by.
+func (f FairnessConfig) trustedTime(clientNanos int64, receivedAt time.Time) time.Time {
+	if !f.Enabled() || !f.ClientTime || clientNanos == 0 {
+		return receivedAt
+	}
+
+	client := time.Unix(0, clientNanos)
+	if earliest := receivedAt.Add(-f.MaxClockSkew); client.Before(earliest) {
+		return earliest
+	}
+	if client.After(receivedAt) {
+		return receivedAt
+	}
+	return client
+}
+
+// fairQueue is a min-heap of buffered submissions ordered by trusted time,
+// then by arrival sequence.
+type fairQueue []GameEvent
+
+func (q fairQueue) Len() int { return len(q) }
+
+func (q fairQueue) Less(i, j int) bool {
+	if !q[i].TrustedAt.Equal(q[j].TrustedAt) {
+		return q[i].TrustedAt.Before(q[j].TrustedAt)
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q fairQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *fairQueue) Push(x any) { *q = append(*q, x.(GameEvent)) }
+
+func (q *fairQueue) Pop() any {
+	old := *q
+	n := len(old)
+	event := old[n-1]
+	*q = old[:n-1]
+	return event
+}
+
+// buffer holds a submission until its reorder window has passed. Only called
+// from the event loop.
+func (g *GameEngine) buffer(event GameEvent) {
+	heap.Push(&g.fairQueue, event)
+	atomic.AddInt64(&g.buffered, 1)
+}
+
+// release judges every buffered submission whose trusted time is at or
+// before cutoff, earliest first. Only called from the event loop.
+func (g *GameEngine) release(cutoff time.Time) {
+	for g.fairQueue.Len() > 0 && !g.fairQueue[0].TrustedAt.After(cutoff) {
+		g.handleEvent(g.popBuffered())
+	}
+}
+
+// popBuffered removes the earliest buffered submission. Only called from the
+// event loop.
+func (g *GameEngine) popBuffered() GameEvent {
+	atomic.AddInt64(&g.buffered, -1)
+	return heap.Pop(&g.fairQueue).(GameEvent)
+}
+
+// drain moves everything waiting on eventChan or in the overflow buffer into
+// the engine and judges all buffered submissions regardless of their window.
+// Only called from the event loop.
+func (g *GameEngine) drain() {
+	for {
+		g.drainQueue()
+		spilled := g.takeSpill()
+		if len(spilled) == 0 {
+			break
+		}
+		for _, event := range spilled {
+			g.dispatch(event)
+		}
+	}
+	for g.fairQueue.Len() > 0 {
+		g.handleEvent(g.popBuffered())
+	}
+}
+
+// discard turns away everything waiting on eventChan, in the overflow buffer
+// or in the fairness buffer with StatusReset. Must be called with g.mu held,
+// and only from the event loop.
+func (g *GameEngine) discard() {
+	for {
+		select {
+		case event := <-g.eventChan:
+			g.turnAway(event)
+			continue
+		default:
+		}
+		spilled := g.takeSpill()
+		if len(spilled) == 0 {
+			break
+		}
+		for _, event := range spilled {
+			g.turnAway(event)
+		}
+	}
+	for g.fairQueue.Len() > 0 {
+		g.turnAway(g.popBuffered())
+	}
+}
+
+// turnAway rejects a discarded submission with StatusReset. Must be called
+// with g.mu held, and only from the event loop.
+func (g *GameEngine) turnAway(event GameEvent) {
+	result := api_server.SubmitResult{Status: api_server.StatusReset}
+	event.Response.TeamID = g.joinTeam(event.Response)
+	user := g.users.received(event.Response, event.Time)
+	user.Rejected++
+	user.answered(result, event.Time, g.clock.Now())
+	event.reply <- result
+
+	event.Response.IsCorrect = false
+	event.Result = result
+	event.reply = nil
+	g.emit(event)
+}
+
+// flush asks the event loop to judge every submission received so far and
+// waits until it has. It must not be called from the event loop or with
+// g.mu held.
+func (g *GameEngine) flush() {
+	done := make(chan struct{})
+	select {
+	case g.flushChan <- done:
+	case <-g.stopChan:
+		return
+	}
+
+	select {
+	case <-done:
+	case <-g.stopChan:
+	}
+}
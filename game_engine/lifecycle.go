@@ -0,0 +1,212 @@
+package game_engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// GameState is the lifecycle position of the current question.
+type GameState int
+
+const (
+	// StatePending means the question is loaded but not yet accepting answers.
+	StatePending GameState = iota
+	// StateOpen means submissions are being accepted.
+	StateOpen
+	// StateClosed means submissions are rejected and the result is final.
+	StateClosed
+	// StateScored means the closed question's result has been recorded.
+	StateScored
+	// StateFinished means the tournament has ended and the leaderboard is
+	// final.
+	StateFinished
+	// StatePaused means an open question is on hold: submissions are
+	// rejected and its clock is stopped.
+	StatePaused
+	// StateCountdown means the question is scheduled to open at a set time;
+	// submissions are rejected until then.
+	StateCountdown
+)
+
+func (s GameState) String() string {
+	switch s {
+	case StatePending:
+		return "pending"
+	case StateOpen:
+		return "open"
+	case StateClosed:
+		return "closed"
+	case StateScored:
+		return "scored"
+	case StateFinished:
+		return "finished"
+	case StatePaused:
+		return "paused"
+	case StateCountdown:
+		return "countdown"
+	default:
+		return fmt.Sprintf("GameState(%d)", int(s))
+	}
+}
+
+// EventType identifies what a GameEvent describes.
+type EventType string
+
+const (
+	EventResponse EventType = "response"
+	EventOpened   EventType = "opened"
+	EventClosed   EventType = "closed"
+	EventScored   EventType = "scored"
+	EventReset    EventType = "reset"
+
+	EventRoundStarted EventType = "round_started"
+	EventFinished     EventType = "finished"
+
+	// EventWinner reports a podium place being awarded.
+	EventWinner EventType = "winner"
+	// EventStatsTick carries a periodic Stats snapshot.
+	EventStatsTick EventType = "stats_tick"
+	// EventStopped is the last event of a stopped engine and carries its
+	// final Stats.
+	EventStopped EventType = "stopped"
+
+	EventPaused  EventType = "paused"
+	EventResumed EventType = "resumed"
+
+	EventCountdown         EventType = "countdown"
+	EventScheduleCancelled EventType = "schedule_cancelled"
+)
+
+// ErrInvalidTransition is returned when a lifecycle operation is not allowed
+// from the engine's current state.
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// validTransitions lists the forward moves of the lifecycle, plus cancelling
+// a countdown. Reset, NextRound and RestartTournament return to StatePending
+// and are handled separately.
+var validTransitions = map[GameState][]GameState{
+	StatePending:   {StateOpen, StateCountdown},
+	StateCountdown: {StateOpen, StatePending},
+	StateOpen:      {StateClosed, StatePaused},
+	StatePaused:    {StateOpen, StateClosed},
+	StateClosed:    {StateScored},
+	StateScored:    {StateFinished},
+}
+
+// transition moves the engine to the target state and emits event, filling
+// in its From, To and Time. Must be called with g.mu held.
+func (g *GameEngine) transition(to GameState, event GameEvent) error {
+	from := g.state
+	if err := g.canTransition(to); err != nil {
+		return err
+	}
+
+	g.state = to
+	event.From, event.To, event.Time = from, to, g.clock.Now()
+	g.emit(event)
+	return nil
+}
+
+// canTransition reports whether the engine may move to the target state.
+// Must be called with g.mu held.
+func (g *GameEngine) canTransition(to GameState) error {
+	if !slices.Contains(validTransitions[g.state], to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, g.state, to)
+	}
+	return nil
+}
+
+// closed reports whether the current round has stopped for good. Must be
+// called with g.mu held.
+func (g *GameEngine) closed() bool {
+	switch g.state {
+	case StateClosed, StateScored, StateFinished:
+		return true
+	}
+	return false
+}
+
+// State returns the current lifecycle state.
+func (g *GameEngine) State() GameState {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.state
+}
+
+// Open starts accepting submissions for the current question. If the
+// question has a time limit, the round closes itself at the deadline. A
+// round in StateCountdown may be opened early.
+func (g *GameEngine) Open() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.open(g.clock.Now())
+}
+
+// open moves the round to StateOpen as of now. A scheduled round is timed
+// from now instead of from its first response. Must be called with g.mu
+// held.
+func (g *GameEngine) open(now time.Time) error {
+	if err := g.transition(StateOpen, GameEvent{Type: EventOpened}); err != nil {
+		return err
+	}
+	g.openedAt = &now
+	if g.startsAt != nil {
+		g.stopSchedule()
+		g.startsAt = nil
+		g.startTime = &now
+	}
+	g.startDeadline(now)
+	return nil
+}
+
+// Close stops accepting submissions for the current question. Submissions
+// already received, including any held in the fairness buffer, are judged
+// first, then the winner strategy fills any remaining podium places.
+func (g *GameEngine) Close() error {
+	g.flush()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.closeRound(CloseReasonHost)
+}
+
+// closeRound moves an open or paused round to StateClosed and lets the winner
+// strategy fill the podium. The closed event is emitted once the podium is
+// final. Must be called with g.mu held.
+func (g *GameEngine) closeRound(reason string) error {
+	if err := g.canTransition(StateClosed); err != nil {
+		return err
+	}
+
+	from := g.state
+	g.state = StateClosed
+	g.stopDeadline()
+	g.endPause(g.clock.Now())
+	g.closeReason = reason
+	for _, s := range g.strategy.Decide() {
+		g.place(s, s.At)
+	}
+
+	now := g.clock.Now()
+	stats := g.snapshot(now)
+	g.emit(GameEvent{Type: EventClosed, From: from, To: StateClosed, Time: now, Reason: reason, Stats: &stats})
+	return nil
+}
+
+// Score records the closed round's result and awards its points to the
+// tournament leaderboard.
+func (g *GameEngine) Score() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.transition(StateScored, GameEvent{Type: EventScored}); err != nil {
+		return err
+	}
+	g.scoreRound()
+	return nil
+}
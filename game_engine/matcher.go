@@ -0,0 +1,162 @@
+package game_engine
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MatchRule names the rule that accepted an answer.
+type MatchRule string
+
+const (
+	MatchExact        MatchRule = "exact"
+	MatchNormalized   MatchRule = "normalized"
+	MatchNumeric      MatchRule = "numeric"
+	MatchSynonym      MatchRule = "synonym"
+	MatchEditDistance MatchRule = "edit_distance"
+)
+
+// MatchOptions configures how loosely a question compares answers. The zero
+// value compares exactly, ignoring surrounding whitespace.
+type MatchOptions struct {
+	// FoldCase compares case-insensitively.
+	FoldCase bool
+	// FoldSpace collapses runs of internal whitespace to a single space.
+	FoldSpace bool
+	// Normalize applies Unicode NFKC normalization, so compatibility forms
+	// such as full-width digits match their plain equivalents.
+	Normalize bool
+	// FoldAccents strips diacritics, so "café" matches "cafe".
+	FoldAccents bool
+	// Numeric compares answers that parse as numbers by value, so "42.0"
+	// matches "42".
+	Numeric bool
+	// NumericTolerance is the largest absolute difference Numeric accepts.
+	NumericTolerance float64
+	// Synonyms maps an accepted answer to alternatives that also count.
+	Synonyms map[string][]string
+	// MaxEditDistance accepts answers within this many single-character
+	// edits of an accepted answer. Zero disables it.
+	MaxEditDistance int
+}
+
+// MatchResult explains the outcome of matching an answer.
+type MatchResult struct {
+	Matched bool
+	// Rule is the first rule that accepted the answer.
+	Rule MatchRule
+	// Accepted is the accepted answer it was matched to.
+	Accepted string
+}
+
+// Match checks answer against the accepted answers, trying each enabled rule
+// from strictest to loosest.
+func (q Question) Match(answer string) MatchResult {
+	opts := q.Matching
+	answer = strings.TrimSpace(answer)
+
+	for _, accepted := range q.AcceptedAnswers {
+		if answer == accepted {
+			return MatchResult{Matched: true, Rule: MatchExact, Accepted: accepted}
+		}
+	}
+
+	folded := opts.fold(answer)
+	for _, accepted := range q.AcceptedAnswers {
+		if folded == opts.fold(accepted) {
+			return MatchResult{Matched: true, Rule: MatchNormalized, Accepted: accepted}
+		}
+	}
+
+	if opts.Numeric {
+		if value, ok := parseNumber(folded); ok {
+			for _, accepted := range q.AcceptedAnswers {
+				if want, ok := parseNumber(opts.fold(accepted)); ok && math.Abs(value-want) <= opts.NumericTolerance {
+					return MatchResult{Matched: true, Rule: MatchNumeric, Accepted: accepted}
+				}
+			}
+		}
+	}
+
+	for _, accepted := range q.AcceptedAnswers {
+		for _, synonym := range opts.Synonyms[accepted] {
+			if folded == opts.fold(synonym) {
+				return MatchResult{Matched: true, Rule: MatchSynonym, Accepted: accepted}
+			}
+		}
+	}
+
+	if opts.MaxEditDistance > 0 {
+		for _, accepted := range q.AcceptedAnswers {
+			if editDistance(folded, opts.fold(accepted)) <= opts.MaxEditDistance {
+				return MatchResult{Matched: true, Rule: MatchEditDistance, Accepted: accepted}
+			}
+		}
+	}
+
+	return MatchResult{}
+}
+
+// fold applies the enabled normalizations to s.
+func (o MatchOptions) fold(s string) string {
+	s = strings.TrimSpace(s)
+	if o.Normalize {
+		s = norm.NFKC.String(s)
+	}
+	if o.FoldAccents {
+		s = stripAccents(s)
+	}
+	if o.FoldCase {
+		s = strings.ToLower(s)
+	}
+	if o.FoldSpace {
+		s = strings.Join(strings.Fields(s), " ")
+	}
+	return s
+}
+
+// stripAccents removes combining marks after canonical decomposition.
+func stripAccents(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+func parseNumber(s string) (float64, bool) {
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+// editDistance returns the Levenshtein distance between a and b in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
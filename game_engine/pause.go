@@ -0,0 +1,87 @@
+package game_engine
+
+import (
+	"time"
+)
+
+// pauseInterval is a span during which the round was paused. to is zero
+// while the pause is ongoing.
+type pauseInterval struct {
+	from time.Time
+	to   time.Time
+}
+
+// Pause stops an open round: submissions are rejected with StatusPaused and
+// the deadline stops counting down. Submissions already received are judged
+// first. Time spent paused is excluded from answer times and time to win.
+func (g *GameEngine) Pause() error {
+	g.flush()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.transition(StatePaused, GameEvent{Type: EventPaused}); err != nil {
+		return err
+	}
+	g.stopDeadline()
+	g.pauses = append(g.pauses, pauseInterval{from: g.clock.Now()})
+	return nil
+}
+
+// Resume reopens a paused round, pushing its deadline back by the time spent
+// paused.
+func (g *GameEngine) Resume() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.transition(StateOpen, GameEvent{Type: EventResumed}); err != nil {
+		return err
+	}
+	now := g.clock.Now()
+	paused := g.endPause(now)
+	if g.deadline != nil {
+		deadline := g.deadline.Add(paused)
+		g.deadline = &deadline
+		g.armDeadline(deadline.Sub(now))
+	}
+	return nil
+}
+
+// endPause closes the ongoing pause at now, if there is one, and returns how
+// long it lasted. Must be called with g.mu held.
+func (g *GameEngine) endPause(now time.Time) time.Duration {
+	n := len(g.pauses)
+	if n == 0 || !g.pauses[n-1].to.IsZero() {
+		return 0
+	}
+	g.pauses[n-1].to = now
+	return now.Sub(g.pauses[n-1].from)
+}
+
+// pausedAt returns when the ongoing pause began, or the zero time. Must be
+// called with g.mu held.
+func (g *GameEngine) pausedAt() time.Time {
+	if n := len(g.pauses); n > 0 && g.pauses[n-1].to.IsZero() {
+		return g.pauses[n-1].from
+	}
+	return time.Time{}
+}
+
+// activeBetween returns the time from from to to, less any part of it the
+// round spent paused. Must be called with g.mu held.
+func (g *GameEngine) activeBetween(from, to time.Time) time.Duration {
+	active := to.Sub(from)
+	for _, p := range g.pauses {
+		start, end := p.from, p.to
+		if end.IsZero() || end.After(to) {
+			end = to
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(start) {
+			active -= end.Sub(start)
+		}
+	}
+	return active
+}
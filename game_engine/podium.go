@@ -0,0 +1,85 @@
+package game_engine
+
+import (
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// PodiumEntry is one winner slot of the current round.
+type PodiumEntry struct {
+	Rank      int                     `json:"rank"`
+	Response  api_server.UserResponse `json:"response"`
+	WonAt     time.Time               `json:"won_at"`
+	TimeToWin time.Duration           `json:"time_to_win_ns"`
+	// Latency is how long the engine took from receiving the submission to
+	// placing it on the podium.
+	Latency time.Duration `json:"latency_ns"`
+	// MatchRule is the rule that accepted the winning answer.
+	MatchRule MatchRule `json:"match_rule,omitempty"`
+}
+
+// place puts a winning submission on the podium, won at now, if a slot is
+// free and the user does not already hold one. It returns the rank awarded,
+// or 0. Must be called with g.mu held.
+func (g *GameEngine) place(s Submission, now time.Time) int {
+	response := s.Response
+	if len(g.podium) >= g.winnerSlots || g.rankOf(response.UserID) > 0 {
+		return 0
+	}
+
+	var timeToWin time.Duration
+	if g.startTime != nil {
+		// A fairness-mode timestamp can predate the first judged response.
+		timeToWin = max(g.activeBetween(*g.startTime, now), 0)
+	}
+
+	entry := PodiumEntry{
+		Rank:      len(g.podium) + 1,
+		Response:  response,
+		WonAt:     now,
+		TimeToWin: timeToWin,
+		Latency:   g.clock.Since(s.ReceivedAt),
+		MatchRule: s.MatchRule,
+	}
+	g.podium = append(g.podium, entry)
+
+	if entry.Rank == 1 {
+		g.winner = &entry.Response
+		g.winnerFoundAt = &now
+	}
+
+	stats := g.snapshot(now)
+	g.emit(GameEvent{Type: EventWinner, Response: response, Time: now, Podium: &entry, Stats: &stats})
+
+	return entry.Rank
+}
+
+// rankOf returns the podium rank held by userID, or 0. Must be called with
+// g.mu held.
+func (g *GameEngine) rankOf(userID int) int {
+	for _, entry := range g.podium {
+		if entry.Response.UserID == userID {
+			return entry.Rank
+		}
+	}
+	return 0
+}
+
+// pointsForRank returns the leaderboard points a podium rank earns.
+func (g *GameEngine) pointsForRank(rank int) int {
+	if rank <= len(g.rankPoints) {
+		return g.rankPoints[rank-1]
+	}
+	return g.pointsPerWin
+}
+
+// GetPodium returns the current round's winners in rank order.
+func (g *GameEngine) GetPodium() []PodiumEntry {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	podium := make([]PodiumEntry, len(g.podium))
+	copy(podium, g.podium)
+	return podium
+}
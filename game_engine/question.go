@@ -0,0 +1,50 @@
+package game_engine
+
+import (
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// Question is a single prompt together with the answers the engine accepts
+// as correct. Correctness is always decided here, never by the client.
+type Question struct {
+	ID     string
+	Prompt string
+	// Type selects validation and judging rules. Empty means TypeFreeText.
+	Type QuestionType
+	// Choices are the options of a choice question or the items of an
+	// ordering question.
+	Choices []string
+	// Range, if set, makes every number inside it correct for a numeric
+	// question.
+	Range           *NumericRange
+	AcceptedAnswers []string
+	// TimeLimit overrides Config.TimeLimit for this question when non-zero.
+	TimeLimit time.Duration
+	// Matching controls how loosely answers are compared.
+	Matching MatchOptions
+}
+
+// IsCorrect reports whether answer is correct under the question's type and
+// matching rules. Choice and ordering answers are comma-separated.
+func (q Question) IsCorrect(answer string) bool {
+	response := api_server.UserResponse{Answer: answer}
+	return q.Validate(response) == nil && q.Evaluate(response).Matched
+}
+
+// QuestionBank is the ordered list of questions an engine serves.
+type QuestionBank []Question
+
+// DefaultQuestionBank returns the fixture used by the user simulator, which
+// answers with "42", "correct", "true" or "yes" when it means to be right.
+func DefaultQuestionBank() QuestionBank {
+	return QuestionBank{
+		{
+			ID:              "q1",
+			Prompt:          "What is the answer to life, the universe and everything?",
+			AcceptedAnswers: []string{"42", "correct", "true", "yes"},
+			Matching:        MatchOptions{FoldCase: true, FoldSpace: true, Numeric: true},
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package game_engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// QuestionType selects how a question's answers are validated and judged.
+type QuestionType string
+
+const (
+	// TypeFreeText judges Answer with the question's MatchOptions. It is
+	// the default.
+	TypeFreeText QuestionType = "free_text"
+	// TypeSingleChoice expects exactly one of Choices.
+	TypeSingleChoice QuestionType = "single_choice"
+	// TypeMultiChoice expects a set of distinct Choices and is correct only
+	// if it equals the set of accepted answers.
+	TypeMultiChoice QuestionType = "multi_choice"
+	// TypeNumeric expects a number, correct if inside Range or numerically
+	// equal to an accepted answer within Matching.NumericTolerance.
+	TypeNumeric QuestionType = "numeric"
+	// TypeOrdering expects every one of Choices exactly once, correct if in
+	// the order given by AcceptedAnswers.
+	TypeOrdering QuestionType = "ordering"
+)
+
+// Rules reported for typed questions.
+const (
+	MatchChoice MatchRule = "choice"
+	MatchRange  MatchRule = "range"
+	MatchOrder  MatchRule = "order"
+)
+
+// ErrInvalidAnswer is returned when a submission does not fit the open
+// question's type.
+var ErrInvalidAnswer = errors.New("invalid answer")
+
+// NumericRange is an inclusive range of correct numeric answers.
+type NumericRange struct {
+	Min float64
+	Max float64
+}
+
+// selections returns the items a choice or ordering submission picked: the
+// Selections field if set, otherwise Answer split on commas.
+func selections(response api_server.UserResponse) []string {
+	items := response.Selections
+	if len(items) == 0 && strings.TrimSpace(response.Answer) != "" {
+		items = strings.Split(response.Answer, ",")
+	}
+
+	trimmed := make([]string, len(items))
+	for i, item := range items {
+		trimmed[i] = strings.TrimSpace(item)
+	}
+	return trimmed
+}
+
+// Validate checks that a submission's payload fits the question's type.
+func (q Question) Validate(response api_server.UserResponse) error {
+	switch q.Type {
+	case TypeSingleChoice:
+		picked := selections(response)
+		if len(picked) != 1 {
+			return fmt.Errorf("%w: exactly one choice required", ErrInvalidAnswer)
+		}
+		if !slices.Contains(q.Choices, picked[0]) {
+			return fmt.Errorf("%w: unknown choice %q", ErrInvalidAnswer, picked[0])
+		}
+	case TypeMultiChoice:
+		picked := selections(response)
+		if len(picked) == 0 {
+			return fmt.Errorf("%w: at least one choice required", ErrInvalidAnswer)
+		}
+		seen := make(map[string]bool, len(picked))
+		for _, item := range picked {
+			if !slices.Contains(q.Choices, item) {
+				return fmt.Errorf("%w: unknown choice %q", ErrInvalidAnswer, item)
+			}
+			if seen[item] {
+				return fmt.Errorf("%w: choice %q selected twice", ErrInvalidAnswer, item)
+			}
+			seen[item] = true
+		}
+	case TypeNumeric:
+		if _, ok := parseNumber(q.Matching.fold(response.Answer)); !ok {
+			return fmt.Errorf("%w: %q is not a number", ErrInvalidAnswer, response.Answer)
+		}
+	case TypeOrdering:
+		picked := selections(response)
+		if len(picked) != len(q.Choices) {
+			return fmt.Errorf("%w: all %d items must be ordered", ErrInvalidAnswer, len(q.Choices))
+		}
+		sorted := slices.Sorted(slices.Values(picked))
+		if !slices.Equal(sorted, slices.Sorted(slices.Values(q.Choices))) {
+			return fmt.Errorf("%w: ordering must contain each item exactly once", ErrInvalidAnswer)
+		}
+	}
+	return nil
+}
+
+// Evaluate judges a submission that passed Validate.
+func (q Question) Evaluate(response api_server.UserResponse) MatchResult {
+	switch q.Type {
+	case TypeSingleChoice:
+		picked := selections(response)
+		if len(picked) == 1 && slices.Contains(q.AcceptedAnswers, picked[0]) {
+			return MatchResult{Matched: true, Rule: MatchChoice, Accepted: picked[0]}
+		}
+	case TypeMultiChoice:
+		picked := slices.Sorted(slices.Values(selections(response)))
+		if slices.Equal(picked, slices.Sorted(slices.Values(q.AcceptedAnswers))) {
+			return MatchResult{Matched: true, Rule: MatchChoice, Accepted: strings.Join(q.AcceptedAnswers, ",")}
+		}
+	case TypeNumeric:
+		if q.Range != nil {
+			value, ok := parseNumber(q.Matching.fold(response.Answer))
+			if ok && value >= q.Range.Min && value <= q.Range.Max {
+				return MatchResult{Matched: true, Rule: MatchRange, Accepted: strconv.FormatFloat(value, 'g', -1, 64)}
+			}
+		}
+		numeric := q
+		numeric.Matching.Numeric = true
+		return numeric.Match(response.Answer)
+	case TypeOrdering:
+		if slices.Equal(selections(response), q.AcceptedAnswers) {
+			return MatchResult{Matched: true, Rule: MatchOrder, Accepted: strings.Join(q.AcceptedAnswers, ",")}
+		}
+	default:
+		return q.Match(response.Answer)
+	}
+	return MatchResult{}
+}
@@ -0,0 +1,170 @@
+package game_engine
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// Queue defaults used when QueueConfig fields are zero.
+const (
+	DefaultQueueCapacity = 1000
+	DefaultBlockTimeout  = time.Second
+	DefaultRetryAfter    = time.Second
+)
+
+// OverflowPolicy selects what happens to a submission that arrives while the
+// event queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits up to BlockTimeout for room, then rejects.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowReject turns the submission away at once with
+	// StatusOverloaded and a RetryAfter hint.
+	OverflowReject
+	// OverflowDrop discards the submission with StatusDropped.
+	OverflowDrop
+	// OverflowSpill parks the submission in an overflow buffer the event
+	// loop works through, in arrival order, once the queue has room.
+	OverflowSpill
+)
+
+// QueueConfig sizes the event queue and chooses its overflow policy.
+type QueueConfig struct {
+	// Capacity is the number of submissions the queue holds. Zero means
+	// DefaultQueueCapacity.
+	Capacity int
+	Policy   OverflowPolicy
+	// BlockTimeout bounds how long OverflowBlock waits. Zero means
+	// DefaultBlockTimeout.
+	BlockTimeout time.Duration
+	// RetryAfter is suggested to clients whose submission was rejected.
+	// Zero means DefaultRetryAfter.
+	RetryAfter time.Duration
+	// SpillCapacity caps the OverflowSpill buffer; submissions beyond it are
+	// rejected. Zero means unbounded.
+	SpillCapacity int
+}
+
+// withDefaults fills in zero fields.
+func (c QueueConfig) withDefaults() QueueConfig {
+	if c.Capacity <= 0 {
+		c.Capacity = DefaultQueueCapacity
+	}
+	if c.BlockTimeout <= 0 {
+		c.BlockTimeout = DefaultBlockTimeout
+	}
+	if c.RetryAfter <= 0 {
+		c.RetryAfter = DefaultRetryAfter
+	}
+	return c
+}
+
+// enqueue hands a submission to the event loop, applying the overflow policy
+// if the queue is full. It returns false with the caller's result if the
+// submission was not accepted. Must be called with g.sendMu held for
+// reading.
+func (g *GameEngine) enqueue(event GameEvent) (api_server.SubmitResult, bool) {
+	if g.queue.Policy == OverflowSpill {
+		return g.enqueueOrSpill(event)
+	}
+
+	select {
+	case g.eventChan <- event:
+		return api_server.SubmitResult{}, true
+	default:
+	}
+	atomic.AddInt64(&g.overflows, 1)
+
+	switch g.queue.Policy {
+	case OverflowBlock:
+		timer := g.clock.NewTimer(g.queue.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case g.eventChan <- event:
+			return api_server.SubmitResult{}, true
+		case <-timer.C():
+			return g.overloaded(), false
+		}
+	case OverflowDrop:
+		atomic.AddInt64(&g.overflowDropped, 1)
+		return api_server.SubmitResult{Status: api_server.StatusDropped}, false
+	default:
+		return g.overloaded(), false
+	}
+}
+
+// enqueueOrSpill sends a submission to the queue, or to the overflow buffer
+// if the queue is full or the buffer already holds earlier submissions, so
+// that arrival order is kept.
+func (g *GameEngine) enqueueOrSpill(event GameEvent) (api_server.SubmitResult, bool) {
+	g.spillMu.Lock()
+	defer g.spillMu.Unlock()
+
+	if len(g.spill) == 0 {
+		select {
+		case g.eventChan <- event:
+			return api_server.SubmitResult{}, true
+		default:
+		}
+	}
+	atomic.AddInt64(&g.overflows, 1)
+
+	if g.queue.SpillCapacity > 0 && len(g.spill) >= g.queue.SpillCapacity {
+		return g.overloaded(), false
+	}
+	g.spill = append(g.spill, event)
+	select {
+	case g.spillReady <- struct{}{}:
+	default:
+	}
+	return api_server.SubmitResult{}, true
+}
+
+// overloaded records and returns a rejection for a full queue.
+func (g *GameEngine) overloaded() api_server.SubmitResult {
+	atomic.AddInt64(&g.overflowRejected, 1)
+	return api_server.SubmitResult{Status: api_server.StatusOverloaded, RetryAfter: g.queue.RetryAfter}
+}
+
+// takeSpill removes and returns everything in the overflow buffer.
+func (g *GameEngine) takeSpill() []GameEvent {
+	g.spillMu.Lock()
+	defer g.spillMu.Unlock()
+
+	spilled := g.spill
+	g.spill = nil
+	return spilled
+}
+
+// spillDepth returns the number of submissions in the overflow buffer.
+func (g *GameEngine) spillDepth() int {
+	g.spillMu.Lock()
+	defer g.spillMu.Unlock()
+
+	return len(g.spill)
+}
+
+// unspill dispatches everything on eventChan, which arrived before anything
+// spilled, then the overflow buffer. Only called from the event loop.
+func (g *GameEngine) unspill() {
+	g.drainQueue()
+	for _, event := range g.takeSpill() {
+		g.dispatch(event)
+	}
+}
+
+// drainQueue dispatches everything waiting on eventChan. Only called from the
+// event loop.
+func (g *GameEngine) drainQueue() {
+	for {
+		select {
+		case event := <-g.eventChan:
+			g.dispatch(event)
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package game_engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+var (
+	// ErrGameExists is returned when creating a game whose ID is taken.
+	ErrGameExists = api_server.ErrGameExists
+	// ErrGameNotFound is returned when no game has the requested ID.
+	ErrGameNotFound = api_server.ErrGameNotFound
+)
+
+// Registry hosts independent game engines keyed by game ID.
+type Registry struct {
+	ctx       context.Context
+	mu        sync.RWMutex
+	games     map[string]*GameEngine
+	newConfig func() Config
+}
+
+// NewRegistry creates an empty registry. Its games are created with ctx, so
+// cancelling it stops them all. newConfig supplies the configuration for
+// games created over the API; it is called once per game so stateful
+// settings such as winner strategies are never shared. Nil means
+// DefaultConfig.
+func NewRegistry(ctx context.Context, newConfig func() Config) *Registry {
+	if newConfig == nil {
+		newConfig = DefaultConfig
+	}
+	return &Registry{
+		ctx:       ctx,
+		games:     make(map[string]*GameEngine),
+		newConfig: newConfig,
+	}
+}
+
+// Create starts a new engine under id with the given configuration.
+func (r *Registry) Create(id string, cfg Config) (*GameEngine, error) {
+	if id == "" {
+		return nil, fmt.Errorf("game ID must not be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.games[id]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrGameExists, id)
+	}
+	engine := NewGameEngineWithConfig(r.ctx, cfg)
+	r.games[id] = engine
+	return engine, nil
+}
+
+// Get returns the engine registered under id.
+func (r *Registry) Get(id string) (*GameEngine, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	engine, ok := r.games[id]
+	return engine, ok
+}
+
+// List returns the IDs of all hosted games in sorted order.
+func (r *Registry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.games))
+	for id := range r.games {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Remove stops the engine registered under id, draining it for up to
+// DefaultDrainTimeout, and forgets it.
+func (r *Registry) Remove(id string) error {
+	r.mu.Lock()
+	engine, ok := r.games[id]
+	delete(r.games, id)
+	r.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrGameNotFound, id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDrainTimeout)
+	defer cancel()
+	if _, err := engine.Stop(ctx); err != nil {
+		return fmt.Errorf("game %s removed but did not drain: %w", id, err)
+	}
+	return nil
+}
+
+// Shutdown stops every hosted engine in parallel, draining each until ctx is
+// done, and empties the registry. It returns each game's summary and the
+// first drain error.
+func (r *Registry) Shutdown(ctx context.Context) (map[string]Summary, error) {
+	r.mu.Lock()
+	games := r.games
+	r.games = make(map[string]*GameEngine)
+	r.mu.Unlock()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		summaries = make(map[string]Summary, len(games))
+		firstErr  error
+	)
+	for id, engine := range games {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summary, err := engine.Stop(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			summaries[id] = summary
+			if err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("game %s: %w", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return summaries, firstErr
+}
+
+// Lookup implements api_server.GameRegistry.
+func (r *Registry) Lookup(id string) (api_server.GameEngineInterface, bool) {
+	engine, ok := r.Get(id)
+	if !ok {
+		return nil, false
+	}
+	return engine, true
+}
+
+// CreateGame implements api_server.GameRegistry. Games created this way use
+// the registry's configuration and are opened immediately, unless it
+// schedules their start. A game that fails to open is removed again.
+func (r *Registry) CreateGame(id string) (api_server.GameEngineInterface, error) {
+	engine, err := r.Create(id, r.newConfig())
+	if err != nil {
+		return nil, err
+	}
+	if engine.State() != StatePending {
+		return engine, nil
+	}
+	if err := engine.Open(); err != nil {
+		r.Remove(id)
+		return nil, err
+	}
+	return engine, nil
+}
+
+// RemoveGame implements api_server.GameRegistry.
+func (r *Registry) RemoveGame(id string) error {
+	return r.Remove(id)
+}
+
+// GameIDs implements api_server.GameRegistry.
+func (r *Registry) GameIDs() []string {
+	return r.List()
+}
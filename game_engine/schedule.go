@@ -0,0 +1,91 @@
+package game_engine
+
+import (
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// ScheduleOpen puts a pending round into StateCountdown and opens it
+// automatically at. Until then submissions are rejected with
+// StatusNotStarted, and the round's clock starts at at rather than at the
+// first response. A time that has already passed opens the round at once.
+func (g *GameEngine) ScheduleOpen(at time.Time) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.clock.Now()
+	if !at.After(now) {
+		return g.open(now)
+	}
+	if err := g.transition(StateCountdown, GameEvent{Type: EventCountdown}); err != nil {
+		return err
+	}
+
+	g.startsAt = &at
+	g.roundGen++
+	gen := g.roundGen
+	g.startTimer = g.clock.AfterFunc(at.Sub(now), func() { g.openScheduled(gen) })
+	return nil
+}
+
+// CancelSchedule returns a round in StateCountdown to StatePending without
+// opening it.
+func (g *GameEngine) CancelSchedule() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.transition(StatePending, GameEvent{Type: EventScheduleCancelled}); err != nil {
+		return err
+	}
+	g.stopSchedule()
+	g.startsAt = nil
+	return nil
+}
+
+// StartsIn returns the time left until a scheduled round opens, or 0 if no
+// countdown is running.
+func (g *GameEngine) StartsIn() time.Duration {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.startsIn(g.clock.Now())
+}
+
+// startsIn returns the countdown to the scheduled opening at now, or 0. Must
+// be called with g.mu held.
+func (g *GameEngine) startsIn(now time.Time) time.Duration {
+	if g.state != StateCountdown || g.startsAt == nil {
+		return 0
+	}
+	return max(g.startsAt.Sub(now), 0)
+}
+
+// openScheduled opens the round scheduled as generation gen, timing it from
+// the scheduled moment rather than from when the timer fired.
+func (g *GameEngine) openScheduled(gen uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.roundGen != gen || g.state != StateCountdown {
+		return
+	}
+	g.open(*g.startsAt)
+}
+
+// notStarted rejects a submission that arrives during the countdown, telling
+// the client when to come back. Must be called with g.mu held.
+func (g *GameEngine) notStarted(now time.Time) api_server.SubmitResult {
+	return api_server.SubmitResult{
+		Status:     api_server.StatusNotStarted,
+		RetryAfter: g.startsIn(now),
+	}
+}
+
+// stopSchedule disarms the countdown timer. Must be called with g.mu held.
+func (g *GameEngine) stopSchedule() {
+	if g.startTimer != nil {
+		g.startTimer.Stop()
+		g.startTimer = nil
+	}
+}
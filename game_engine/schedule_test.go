@@ -0,0 +1,50 @@
+package game_engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/clock"
+)
+
+func TestCreateGameWithStartAt(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	startAt := fake.Now().Add(-time.Minute)
+	registry := NewRegistry(context.Background(), func() Config {
+		cfg := DefaultConfig()
+		cfg.Console = false
+		cfg.Clock = fake
+		cfg.StartAt = startAt
+		return cfg
+	})
+	t.Cleanup(func() { registry.Shutdown(context.Background()) })
+
+	// A start time already passed opens the game straight away.
+	engine, err := registry.CreateGame("past")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state := engine.(*GameEngine).State(); state != StateOpen {
+		t.Errorf("game with a past start is %s, want open", state)
+	}
+
+	startAt = fake.Now().Add(time.Minute)
+	created, err := registry.CreateGame("future")
+	if err != nil {
+		t.Fatal(err)
+	}
+	future := created.(*GameEngine)
+	if state := future.State(); state != StateCountdown {
+		t.Fatalf("game with a future start is %s, want countdown", state)
+	}
+
+	fake.Advance(time.Minute)
+	stats := future.GetStats()
+	if stats.State != StateOpen.String() {
+		t.Errorf("game is %s at its start time, want open", stats.State)
+	}
+	if stats.StartsAt != nil {
+		t.Errorf("starts_at still reported as %v once open", *stats.StartsAt)
+	}
+}
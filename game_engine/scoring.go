@@ -0,0 +1,135 @@
+package game_engine
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DecayCurve selects how speed-bonus points fall off with answer time.
+type DecayCurve int
+
+const (
+	// DecayNone disables speed scoring; only podium places earn points.
+	DecayNone DecayCurve = iota
+	// DecayLinear falls from MaxPoints to MinPoints over Duration.
+	DecayLinear
+	// DecayExponential halves the bonus above MinPoints every HalfLife.
+	DecayExponential
+	// DecayStepped awards the points of the first step answered within.
+	DecayStepped
+)
+
+// ScoreStep is one band of a stepped decay curve.
+type ScoreStep struct {
+	Within time.Duration
+	Points int
+}
+
+// ScoringConfig enables latency-weighted scoring: every correct answer earns
+// points that decay with the time since the question opened, instead of
+// only podium finishers scoring.
+type ScoringConfig struct {
+	Curve     DecayCurve
+	MaxPoints int
+	MinPoints int
+	// Duration is the linear decay span. Zero uses the question's time
+	// limit, or awards MaxPoints throughout if there is none.
+	Duration time.Duration
+	// HalfLife is the exponential decay half-life.
+	HalfLife time.Duration
+	// Steps are the stepped bands, in increasing order of Within. Answers
+	// slower than the last band earn MinPoints.
+	Steps []ScoreStep
+}
+
+// Enabled reports whether speed scoring is on.
+func (c ScoringConfig) Enabled() bool {
+	return c.Curve != DecayNone
+}
+
+// Points returns what a correct answer elapsed after the question opened
+// earns. span is the linear decay span to use when Duration is zero.
+func (c ScoringConfig) Points(elapsed, span time.Duration) int {
+	if c.Duration > 0 {
+		span = c.Duration
+	}
+	bonus := float64(c.MaxPoints - c.MinPoints)
+
+	switch c.Curve {
+	case DecayLinear:
+		if span <= 0 {
+			return c.MaxPoints
+		}
+		fraction := math.Min(float64(elapsed)/float64(span), 1)
+		return c.MinPoints + int(math.Round(bonus*(1-fraction)))
+	case DecayExponential:
+		if c.HalfLife <= 0 {
+			return c.MaxPoints
+		}
+		return c.MinPoints + int(math.Round(bonus*math.Pow(0.5, float64(elapsed)/float64(c.HalfLife))))
+	case DecayStepped:
+		for _, step := range c.Steps {
+			if elapsed <= step.Within {
+				return step.Points
+			}
+		}
+		return c.MinPoints
+	default:
+		return 0
+	}
+}
+
+// awardSpeedPoints credits a user's first correct answer of the round. Must
+// be called with g.mu held.
+func (g *GameEngine) awardSpeedPoints(userID int, elapsed time.Duration) {
+	if !g.scoring.Enabled() {
+		return
+	}
+	if _, ok := g.roundPoints[userID]; ok {
+		return
+	}
+	g.roundPoints[userID] = speedScore{points: g.scoring.Points(elapsed, g.timeLimit()), elapsed: elapsed}
+}
+
+// speedScore is a user's speed-bonus result for the current round.
+type speedScore struct {
+	points  int
+	elapsed time.Duration
+}
+
+// speedStandings ranks the current round by speed points, fastest first on
+// ties. Podium finishers are credited a win. Must be called with g.mu held.
+func (g *GameEngine) speedStandings() []Standing {
+	type entry struct {
+		Standing
+		elapsed time.Duration
+	}
+
+	entries := make([]entry, 0, len(g.roundPoints))
+	for userID, score := range g.roundPoints {
+		s := Standing{UserID: userID, Points: score.points}
+		if g.rankOf(userID) > 0 {
+			s.Wins = 1
+		}
+		entries = append(entries, entry{Standing: s, elapsed: score.elapsed})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.elapsed != b.elapsed {
+			return a.elapsed < b.elapsed
+		}
+		return a.UserID < b.UserID
+	})
+
+	standings := make([]Standing, len(entries))
+	for i, e := range entries {
+		standings[i] = e.Standing
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
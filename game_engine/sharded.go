@@ -0,0 +1,481 @@
+package game_engine
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+	"github.com/glitchdawg/game-engine-with-user/clock"
+)
+
+// ShardedConfig holds the settings a ShardedEngine is created with.
+type ShardedConfig struct {
+	// Question is the question submissions are judged against. The zero
+	// value uses the first question of DefaultQuestionBank.
+	Question Question
+	// Shards is the number of ingest workers. Zero means GOMAXPROCS.
+	Shards int
+	// ShardQueue is the queue capacity of each shard. Zero means
+	// DefaultQueueCapacity.
+	ShardQueue int
+	// Attempts limits how often each user may submit per round.
+	Attempts AttemptPolicy
+	// Clock is the time source. Nil means clock.Real.
+	Clock clock.Clock
+}
+
+// ShardedEngine is a first-correct-answer engine for very large bursts.
+// Users are partitioned by ID across shard workers, which admit and judge
+// submissions in parallel without a shared lock. Correct answers are
+// forwarded to a single arbiter goroutine, which alone declares the winner,
+// so there is exactly one per round however many shards there are.
+//
+// It serves a single question with the Pending, Open and Closed states and
+// does not support tournaments, podiums, teams, elimination, pausing,
+// scheduled starts, fairness ordering or events.
+type ShardedEngine struct {
+	question Question
+	policy   AttemptPolicy
+	shards   []*shard
+	arbiter  chan shardMessage
+	stopChan chan struct{}
+	stopOnce sync.Once
+	clock    clock.Clock
+	// state is a GameState, read by shard workers without a lock.
+	state int32
+	// decided is set once the round has a winner, so shards can answer late
+	// correct submissions without asking the arbiter.
+	decided int32
+
+	mu       sync.RWMutex
+	openedAt *time.Time
+	winner   *PodiumEntry
+}
+
+// shard owns the attempt and user records of the users routed to it. Its
+// counters are written only by its worker.
+type shard struct {
+	events   chan shardMessage
+	verdicts chan api_server.SubmitResult
+	attempts map[int]*attemptRecord
+	users    userRecords
+	clock    clock.Clock
+
+	total    int64
+	correct  int64
+	rejected int64
+	// Keep neighbouring shards' counters off each other's cache lines.
+	_ [64]byte
+}
+
+// shardMessage is a submission, a reset barrier when reset is non-nil, or a
+// user record lookup when lookup is non-nil.
+type shardMessage struct {
+	response  api_server.UserResponse
+	at        time.Time
+	matchRule MatchRule
+	user      *userRecord
+	reply     chan api_server.SubmitResult
+	verdict   chan api_server.SubmitResult
+	reset     chan struct{}
+	lookup    chan *UserRecord
+}
+
+// NewShardedEngine creates a sharded engine in StatePending. Cancelling ctx
+// stops it, as Stop does.
+func NewShardedEngine(ctx context.Context, cfg ShardedConfig) *ShardedEngine {
+	if cfg.Question.ID == "" && len(cfg.Question.AcceptedAnswers) == 0 {
+		cfg.Question = DefaultQuestionBank()[0]
+	}
+	if cfg.Shards <= 0 {
+		cfg.Shards = runtime.GOMAXPROCS(0)
+	}
+	if cfg.ShardQueue <= 0 {
+		cfg.ShardQueue = DefaultQueueCapacity
+	}
+
+	e := &ShardedEngine{
+		question: cfg.Question,
+		policy:   cfg.Attempts,
+		shards:   make([]*shard, cfg.Shards),
+		arbiter:  make(chan shardMessage, cfg.ShardQueue),
+		stopChan: make(chan struct{}),
+		clock:    clock.Or(cfg.Clock),
+	}
+	for i := range e.shards {
+		e.shards[i] = &shard{
+			events:   make(chan shardMessage, cfg.ShardQueue),
+			verdicts: make(chan api_server.SubmitResult, 1),
+			attempts: make(map[int]*attemptRecord),
+			users:    make(userRecords),
+			clock:    e.clock,
+		}
+		go e.runShard(e.shards[i])
+	}
+	go e.arbitrate()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			e.Stop()
+		case <-e.stopChan:
+		}
+	}()
+	return e
+}
+
+// shardFor returns the shard a user's submissions are routed to.
+func (e *ShardedEngine) shardFor(userID int) *shard {
+	n := len(e.shards)
+	return e.shards[((userID%n)+n)%n]
+}
+
+// runShard admits and judges the submissions of one shard in arrival order.
+func (e *ShardedEngine) runShard(s *shard) {
+	for {
+		select {
+		case msg := <-s.events:
+			if msg.reset != nil {
+				s.attempts = make(map[int]*attemptRecord)
+				close(msg.reset)
+				continue
+			}
+			if msg.lookup != nil {
+				msg.lookup <- s.lookup(msg.response.UserID)
+				continue
+			}
+			e.judge(s, msg)
+		case <-e.stopChan:
+			return
+		}
+	}
+}
+
+// judge decides a submission on its shard, handing correct answers to the
+// arbiter while the round has no winner. Only called from the shard worker.
+func (e *ShardedEngine) judge(s *shard, msg shardMessage) {
+	msg.user = s.users.received(msg.response, msg.at)
+
+	switch GameState(atomic.LoadInt32(&e.state)) {
+	case StateOpen:
+	case StatePending:
+		s.reject(msg, api_server.SubmitResult{Status: api_server.StatusNotOpen})
+		return
+	default:
+		s.reject(msg, api_server.SubmitResult{Status: api_server.StatusClosed})
+		return
+	}
+
+	// Invalid payloads do not use up an attempt, as in GameEngine.admission.
+	if err := e.question.Validate(msg.response); err != nil {
+		s.reject(msg, api_server.SubmitResult{Status: api_server.StatusInvalid, Reason: err.Error()})
+		return
+	}
+	if result, ok := e.policy.admit(s.attempts, msg.response.UserID, msg.at); !ok {
+		s.reject(msg, result)
+		return
+	}
+
+	atomic.AddInt64(&s.total, 1)
+	msg.user.Attempts++
+	match := e.question.Evaluate(msg.response)
+	if !match.Matched {
+		s.reply(msg, api_server.SubmitResult{Status: api_server.StatusLost})
+		return
+	}
+	atomic.AddInt64(&s.correct, 1)
+	msg.user.Correct++
+
+	if atomic.LoadInt32(&e.decided) == 1 {
+		s.reply(msg, api_server.SubmitResult{Status: api_server.StatusLate, MatchRule: string(match.Rule)})
+		return
+	}
+	// Wait for the arbiter so the user's record stays owned by this worker.
+	// Only correct answers before the winner is known take this path.
+	msg.response.IsCorrect = true
+	msg.matchRule = match.Rule
+	msg.verdict = s.verdicts
+	select {
+	case e.arbiter <- msg:
+	case <-e.stopChan:
+		return
+	}
+	select {
+	case result := <-s.verdicts:
+		s.reply(msg, result)
+	case <-e.stopChan:
+	}
+}
+
+// reply records a verdict on the user's record and sends it. Only called
+// from the shard worker.
+func (s *shard) reply(msg shardMessage, result api_server.SubmitResult) {
+	msg.user.answered(result, msg.at, s.clock.Now())
+	msg.reply <- result
+}
+
+// reject counts a submission turned away before judging and replies.
+func (s *shard) reject(msg shardMessage, result api_server.SubmitResult) {
+	atomic.AddInt64(&s.rejected, 1)
+	msg.user.Rejected++
+	s.reply(msg, result)
+}
+
+// lookup returns a copy of a user's record, or nil. Only called from the
+// shard worker.
+func (s *shard) lookup(userID int) *UserRecord {
+	record, ok := s.users[userID]
+	if !ok {
+		return nil
+	}
+	user := record.UserRecord
+	return &user
+}
+
+// arbitrate declares the first correct submission it receives the winner,
+// returning each verdict to the shard that asked.
+func (e *ShardedEngine) arbitrate() {
+	for {
+		select {
+		case msg := <-e.arbiter:
+			if msg.reset != nil {
+				close(msg.reset)
+				continue
+			}
+			msg.verdict <- e.decide(msg)
+		case <-e.stopChan:
+			return
+		}
+	}
+}
+
+// decide records msg as the winner if there is none yet. Only called from
+// the arbiter.
+func (e *ShardedEngine) decide(msg shardMessage) api_server.SubmitResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.winner != nil || GameState(atomic.LoadInt32(&e.state)) != StateOpen {
+		return api_server.SubmitResult{Status: api_server.StatusLate, MatchRule: string(msg.matchRule)}
+	}
+
+	now := e.clock.Now()
+	entry := PodiumEntry{
+		Rank:      1,
+		Response:  msg.response,
+		WonAt:     now,
+		Latency:   now.Sub(msg.at),
+		MatchRule: msg.matchRule,
+	}
+	if e.openedAt != nil {
+		entry.TimeToWin = max(now.Sub(*e.openedAt), 0)
+	}
+	e.winner = &entry
+	atomic.StoreInt32(&e.decided, 1)
+	return api_server.SubmitResult{Status: api_server.StatusWon, Rank: 1, MatchRule: string(msg.matchRule)}
+}
+
+// ProcessResponse routes a submission to its user's shard and waits for the
+// verdict. Once the engine is stopped it returns ErrStopped.
+func (e *ShardedEngine) ProcessResponse(response api_server.UserResponse) (api_server.SubmitResult, error) {
+	msg := shardMessage{
+		response: response,
+		at:       e.clock.Now(),
+		reply:    make(chan api_server.SubmitResult, 1),
+	}
+
+	select {
+	case e.shardFor(response.UserID).events <- msg:
+	case <-e.stopChan:
+		return api_server.SubmitResult{}, ErrStopped
+	}
+
+	select {
+	case result := <-msg.reply:
+		return result, nil
+	case <-e.stopChan:
+		return api_server.SubmitResult{}, ErrStopped
+	}
+}
+
+// Open starts accepting submissions.
+func (e *ShardedEngine) Open() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !atomic.CompareAndSwapInt32(&e.state, int32(StatePending), int32(StateOpen)) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, e.State(), StateOpen)
+	}
+	now := e.clock.Now()
+	e.openedAt = &now
+	return nil
+}
+
+// Close stops accepting submissions.
+func (e *ShardedEngine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !atomic.CompareAndSwapInt32(&e.state, int32(StateOpen), int32(StateClosed)) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, e.State(), StateClosed)
+	}
+	return nil
+}
+
+// State returns the current lifecycle state.
+func (e *ShardedEngine) State() GameState {
+	return GameState(atomic.LoadInt32(&e.state))
+}
+
+// GetWinner implements api_server.GameEngineInterface.
+func (e *ShardedEngine) GetWinner() *api_server.UserResponse {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.winner == nil {
+		return nil
+	}
+	winnerCopy := e.winner.Response
+	return &winnerCopy
+}
+
+// Reset returns the engine to StatePending, discarding the winner, counters
+// and attempt records. Submissions queued before the reset are judged first.
+func (e *ShardedEngine) Reset() {
+	atomic.StoreInt32(&e.state, int32(StatePending))
+
+	for _, s := range e.shards {
+		if !e.barrier(s.events) {
+			return
+		}
+	}
+	if !e.barrier(e.arbiter) {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.winner = nil
+	e.openedAt = nil
+	atomic.StoreInt32(&e.decided, 0)
+	for _, s := range e.shards {
+		atomic.StoreInt64(&s.total, 0)
+		atomic.StoreInt64(&s.correct, 0)
+		atomic.StoreInt64(&s.rejected, 0)
+	}
+}
+
+// barrier waits until everything queued on ch before it has been handled. It
+// returns false if the engine stopped first.
+func (e *ShardedEngine) barrier(ch chan shardMessage) bool {
+	done := make(chan struct{})
+	select {
+	case ch <- shardMessage{reset: done}:
+	case <-e.stopChan:
+		return false
+	}
+
+	select {
+	case <-done:
+		return true
+	case <-e.stopChan:
+		return false
+	}
+}
+
+// Stop shuts the engine down. Submissions still queued are abandoned and
+// their callers receive ErrStopped.
+func (e *ShardedEngine) Stop() {
+	e.stopOnce.Do(func() { close(e.stopChan) })
+}
+
+// GetUserRecord returns the submission history of userID, or false if the
+// engine has never received a submission from them. The record is read by
+// the user's shard worker, after everything queued before the call.
+func (e *ShardedEngine) GetUserRecord(userID int) (UserRecord, bool) {
+	lookup := make(chan *UserRecord, 1)
+	select {
+	case e.shardFor(userID).events <- shardMessage{response: api_server.UserResponse{UserID: userID}, lookup: lookup}:
+	case <-e.stopChan:
+		return UserRecord{}, false
+	}
+
+	var user *UserRecord
+	select {
+	case user = <-lookup:
+	case <-e.stopChan:
+	}
+	if user == nil {
+		return UserRecord{}, false
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.winner != nil && e.winner.Response.UserID == userID {
+		user.Rank = 1
+	}
+	return *user, true
+}
+
+// UserSnapshot implements api_server.GameEngineInterface.
+func (e *ShardedEngine) UserSnapshot(userID int) (interface{}, bool) {
+	return e.GetUserRecord(userID)
+}
+
+// GetStats returns a snapshot of the engine. Shard counters are read one at
+// a time, so totals may be mid-update under load.
+func (e *ShardedEngine) GetStats() Stats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	now := e.clock.Now()
+	stats := Stats{
+		TakenAt:     now,
+		State:       e.State().String(),
+		Round:       1,
+		Rounds:      1,
+		QuestionID:  e.question.ID,
+		WinnerSlots: 1,
+	}
+
+	for _, s := range e.shards {
+		stats.TotalResponses += atomic.LoadInt64(&s.total)
+		stats.CorrectResponses += atomic.LoadInt64(&s.correct)
+		stats.Rejected += atomic.LoadInt64(&s.rejected)
+		stats.QueueDepth += len(s.events)
+		stats.QueueCapacity += cap(s.events)
+	}
+
+	if stats.TotalResponses > 0 {
+		stats.CorrectPercentage = float64(stats.CorrectResponses) / float64(stats.TotalResponses) * 100
+	}
+
+	if e.openedAt != nil {
+		stats.GameDuration = now.Sub(*e.openedAt).Seconds()
+		if stats.GameDuration > 0 {
+			stats.ResponsesPerSec = float64(stats.TotalResponses) / stats.GameDuration
+		}
+	}
+
+	stats.NoWinner = e.State() == StateClosed && e.winner == nil
+	if e.winner != nil {
+		stats.HasWinner = true
+		stats.WinnerUserID = e.winner.Response.UserID
+		stats.WinnerAnswer = e.winner.Response.Answer
+		stats.TimeToWin = e.winner.TimeToWin.Seconds()
+		stats.WinnerLatency = e.winner.Latency.Seconds()
+		stats.Podium = []PodiumEntry{*e.winner}
+	}
+
+	return stats
+}
+
+// StatsSnapshot implements api_server.GameEngineInterface.
+func (e *ShardedEngine) StatsSnapshot() interface{} {
+	return e.GetStats()
+}
@@ -0,0 +1,77 @@
+package game_engine
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// benchmarkClients is the number of concurrent submitters per GOMAXPROCS,
+// standing in for many simultaneous HTTP requests.
+const benchmarkClients = 64
+
+// submitter is the ProcessResponse method shared by both engines.
+type submitter interface {
+	ProcessResponse(api_server.UserResponse) (api_server.SubmitResult, error)
+}
+
+// benchmarkSubmissions sends one submission per iteration from distinct
+// users, one in a hundred of them correct.
+func benchmarkSubmissions(b *testing.B, engine submitter) {
+	var nextUser int64
+	b.SetParallelism(benchmarkClients)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			userID := int(atomic.AddInt64(&nextUser, 1))
+			answer := "wrong"
+			if userID%100 == 0 {
+				answer = "42"
+			}
+			if _, err := engine.ProcessResponse(api_server.UserResponse{UserID: userID, Answer: answer}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.StopTimer()
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "submissions/s")
+}
+
+func BenchmarkGameEngine(b *testing.B) {
+	cfg := DefaultConfig()
+	cfg.Console = false
+	engine := NewGameEngineWithConfig(context.Background(), cfg)
+	defer engine.Stop(context.Background())
+	if err := engine.Open(); err != nil {
+		b.Fatal(err)
+	}
+
+	benchmarkSubmissions(b, engine)
+}
+
+func BenchmarkShardedEngine(b *testing.B) {
+	for _, shards := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			engine := NewShardedEngine(context.Background(), ShardedConfig{
+				Shards:   shards,
+				Attempts: SingleAttempt(),
+			})
+			defer engine.Stop()
+			if err := engine.Open(); err != nil {
+				b.Fatal(err)
+			}
+
+			benchmarkSubmissions(b, engine)
+
+			if stats := engine.GetStats(); stats.CorrectResponses > 0 && !stats.HasWinner {
+				b.Fatal("correct answers were submitted but no winner was declared")
+			}
+		})
+	}
+}
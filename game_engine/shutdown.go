@@ -0,0 +1,132 @@
+package game_engine
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultDrainTimeout bounds the graceful stop triggered by cancelling the
+// context an engine was created with.
+const DefaultDrainTimeout = 5 * time.Second
+
+// ErrStopped is returned by ProcessResponse once the engine has begun
+// stopping.
+var ErrStopped = errors.New("game engine stopped")
+
+// Summary is the final result of a stopped engine.
+type Summary struct {
+	StoppedAt time.Time     `json:"stopped_at"`
+	Stats     Stats         `json:"stats"`
+	Results   []RoundResult `json:"results"`
+	// Drained is the number of queued submissions judged during the stop.
+	Drained int `json:"drained"`
+	// Abandoned is the number still queued when the drain timed out. Their
+	// callers receive ErrStopped.
+	Abandoned int `json:"abandoned"`
+}
+
+// stopRequest asks the event loop to drain and exit.
+type stopRequest struct {
+	ctx  context.Context
+	done chan drainResult
+}
+
+type drainResult struct {
+	drained   int
+	abandoned int
+	err       error
+}
+
+// Stop refuses new submissions, judges everything already queued, and stops
+// the engine. Draining gives up when ctx is done, abandoning what is left
+// and returning ctx's error alongside the summary. Only the first call
+// drains; later calls wait for it and return the same result.
+func (g *GameEngine) Stop(ctx context.Context) (Summary, error) {
+	g.stopOnce.Do(func() {
+		g.sendMu.Lock()
+		g.stopping = true
+		g.sendMu.Unlock()
+
+		done := make(chan drainResult, 1)
+		g.stopReq <- stopRequest{ctx: ctx, done: done}
+		result := <-done
+		close(g.stopChan)
+
+		g.mu.Lock()
+		g.stopDeadline()
+		g.stopSchedule()
+		stats := g.snapshot(g.clock.Now())
+		g.summary = Summary{
+			StoppedAt: stats.TakenAt,
+			Stats:     stats,
+			Results:   append([]RoundResult{}, g.results...),
+			Drained:   result.drained,
+			Abandoned: result.abandoned,
+		}
+		g.stopErr = result.err
+		g.emit(GameEvent{Type: EventStopped, Time: stats.TakenAt, Stats: &stats})
+		g.mu.Unlock()
+
+		g.closeSubscribers()
+	})
+	return g.summary, g.stopErr
+}
+
+// stopWhenDone stops the engine, with DefaultDrainTimeout or the configured
+// drain timeout, once ctx is cancelled.
+func (g *GameEngine) stopWhenDone(ctx context.Context, timeout time.Duration) {
+	select {
+	case <-ctx.Done():
+		drainCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		g.Stop(drainCtx)
+	case <-g.stopChan:
+	}
+}
+
+// drainForStop judges everything on eventChan, then everything in the
+// overflow buffer, then everything in the fairness buffer in trusted-time
+// order, until all are empty or ctx is done. Only called from the event
+// loop, after new submissions are refused.
+func (g *GameEngine) drainForStop(ctx context.Context) drainResult {
+	var (
+		result  drainResult
+		spilled []GameEvent
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			result.abandoned = len(g.eventChan) + len(spilled) + g.spillDepth() + g.fairQueue.Len()
+			result.err = err
+			return result
+		}
+
+		select {
+		case event := <-g.eventChan:
+			g.dispatch(event)
+			if !g.fairness.Enabled() {
+				result.drained++
+			}
+			continue
+		default:
+		}
+
+		if len(spilled) == 0 {
+			spilled = g.takeSpill()
+		}
+		if len(spilled) > 0 {
+			g.dispatch(spilled[0])
+			spilled = spilled[1:]
+			if !g.fairness.Enabled() {
+				result.drained++
+			}
+			continue
+		}
+
+		if g.fairQueue.Len() == 0 {
+			return result
+		}
+		g.handleEvent(g.popBuffered())
+		result.drained++
+	}
+}
@@ -0,0 +1,165 @@
+package game_engine
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a point-in-time snapshot of an engine. Durations are in seconds.
+type Stats struct {
+	TakenAt    time.Time `json:"taken_at"`
+	State      string    `json:"state"`
+	Round      int       `json:"round"`
+	Rounds     int       `json:"rounds"`
+	QuestionID string    `json:"question_id"`
+
+	TotalResponses    int64   `json:"total_responses"`
+	CorrectResponses  int64   `json:"correct_responses"`
+	CorrectPercentage float64 `json:"correct_percentage"`
+	Rejected          int64   `json:"rejected"`
+	ClaimMismatches   int64   `json:"claim_mismatches"`
+	// Users is the number of distinct users who have submitted since the
+	// tournament started.
+	Users int `json:"users"`
+
+	GameDuration     float64 `json:"game_duration"`
+	ResponsesPerSec  float64 `json:"responses_per_sec"`
+	QueueDepth       int     `json:"queue_depth"`
+	FairnessBuffered int64   `json:"fairness_buffered"`
+	// DroppedEvents counts events not delivered to a subscriber whose
+	// buffer was full.
+	DroppedEvents int64 `json:"dropped_events"`
+
+	QueueCapacity int `json:"queue_capacity"`
+	// Overflows counts submissions that found the queue full, however the
+	// overflow policy then handled them.
+	Overflows        int64 `json:"overflows"`
+	OverflowRejected int64 `json:"overflow_rejected"`
+	OverflowDropped  int64 `json:"overflow_dropped"`
+	SpillDepth       int   `json:"spill_depth"`
+
+	// StartsAt is when a scheduled round opens, and StartsIn the countdown
+	// to it.
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	StartsIn float64    `json:"starts_in"`
+
+	Deadline      *time.Time `json:"deadline,omitempty"`
+	TimeRemaining float64    `json:"time_remaining"`
+	NoWinner      bool       `json:"no_winner"`
+	CloseReason   string     `json:"close_reason,omitempty"`
+
+	HasWinner     bool    `json:"has_winner"`
+	WinnerUserID  int     `json:"winner_user_id,omitempty"`
+	WinnerAnswer  string  `json:"winner_answer,omitempty"`
+	TimeToWin     float64 `json:"time_to_win,omitempty"`
+	WinnerLatency float64 `json:"winner_latency,omitempty"`
+
+	WinnerSlots         int           `json:"winner_slots"`
+	Podium              []PodiumEntry `json:"podium"`
+	RoundStandings      []Standing    `json:"round_standings"`
+	TournamentStandings []Standing    `json:"tournament_standings"`
+
+	// FirstTeam is the team of the first user judged correct this round, if
+	// they were on one; see RoundResult.FirstTeam.
+	FirstTeam     string         `json:"first_team,omitempty"`
+	TeamStandings []TeamStanding `json:"team_standings,omitempty"`
+
+	// Elimination game progress: users still in, users knocked out, and
+	// the survivors left after each scored round.
+	Survivors        int   `json:"survivors,omitempty"`
+	Eliminated       int   `json:"eliminated,omitempty"`
+	SurvivorsByRound []int `json:"survivors_by_round,omitempty"`
+}
+
+// GetStats returns a consistent snapshot of the engine: every field is read
+// under the same lock the event loop judges submissions with.
+func (g *GameEngine) GetStats() Stats {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.snapshot(g.clock.Now())
+}
+
+// snapshot builds the Stats for now. Must be called with g.mu held.
+func (g *GameEngine) snapshot(now time.Time) Stats {
+	stats := Stats{
+		TakenAt:    now,
+		State:      g.state.String(),
+		Round:      g.current + 1,
+		Rounds:     g.rounds,
+		QuestionID: g.questions[g.current].ID,
+
+		TotalResponses:   atomic.LoadInt64(&g.totalResponses),
+		CorrectResponses: atomic.LoadInt64(&g.correctResponses),
+		Rejected:         atomic.LoadInt64(&g.rejected),
+		ClaimMismatches:  atomic.LoadInt64(&g.claimMismatches),
+		Users:            len(g.users),
+
+		QueueDepth:       len(g.eventChan),
+		QueueCapacity:    cap(g.eventChan),
+		Overflows:        atomic.LoadInt64(&g.overflows),
+		OverflowRejected: atomic.LoadInt64(&g.overflowRejected),
+		OverflowDropped:  atomic.LoadInt64(&g.overflowDropped),
+		SpillDepth:       g.spillDepth(),
+		FairnessBuffered: atomic.LoadInt64(&g.buffered),
+		DroppedEvents:    atomic.LoadInt64(&g.subs.dropped),
+
+		StartsIn:      g.startsIn(now).Seconds(),
+		TimeRemaining: g.timeRemaining(now).Seconds(),
+		NoWinner:      g.closed() && len(g.podium) == 0,
+		CloseReason:   g.closeReason,
+
+		HasWinner:   g.winner != nil,
+		WinnerSlots: g.winnerSlots,
+		Podium:      append([]PodiumEntry{}, g.podium...),
+
+		RoundStandings:      g.roundStandings(),
+		TournamentStandings: g.tournamentStandings(),
+
+		FirstTeam:     g.firstTeam,
+		TeamStandings: g.teamStandings(),
+	}
+
+	if g.elimination.enabled {
+		stats.Survivors = g.survivorCount()
+		stats.Eliminated = len(g.elimination.eliminatedIn)
+		for _, result := range g.results {
+			stats.SurvivorsByRound = append(stats.SurvivorsByRound, result.Survivors)
+		}
+	}
+
+	if g.startsAt != nil {
+		startsAt := *g.startsAt
+		stats.StartsAt = &startsAt
+	}
+
+	if g.deadline != nil {
+		deadline := *g.deadline
+		stats.Deadline = &deadline
+	}
+
+	if stats.TotalResponses > 0 {
+		stats.CorrectPercentage = float64(stats.CorrectResponses) / float64(stats.TotalResponses) * 100
+	}
+
+	if g.startTime != nil {
+		stats.GameDuration = g.activeBetween(*g.startTime, now).Seconds()
+		if stats.GameDuration > 0 {
+			stats.ResponsesPerSec = float64(stats.TotalResponses) / stats.GameDuration
+		}
+	}
+
+	if g.winner != nil {
+		stats.WinnerUserID = g.winner.UserID
+		stats.WinnerAnswer = g.winner.Answer
+		stats.TimeToWin = g.podium[0].TimeToWin.Seconds()
+		stats.WinnerLatency = g.podium[0].Latency.Seconds()
+	}
+
+	return stats
+}
+
+// StatsSnapshot implements api_server.GameEngineInterface.
+func (g *GameEngine) StatsSnapshot() interface{} {
+	return g.GetStats()
+}
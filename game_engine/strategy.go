@@ -0,0 +1,174 @@
+package game_engine
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// Submission is a judged response as seen by a WinnerStrategy.
+type Submission struct {
+	Response api_server.UserResponse
+	// Correct is the engine's verdict against the current question.
+	Correct bool
+	// At is the submission's trusted timestamp: server receipt time, or the
+	// fairness-mode timestamp when fairness is enabled.
+	At time.Time
+	// Elapsed is At measured from when the question opened.
+	Elapsed time.Duration
+	// ReceivedAt is when the server received the submission.
+	ReceivedAt time.Time
+	// MatchRule is the rule that accepted the answer, empty if incorrect.
+	MatchRule MatchRule
+}
+
+// Verdict is a WinnerStrategy's decision on a single submission.
+type Verdict int
+
+const (
+	// VerdictLose means the submission cannot win.
+	VerdictLose Verdict = iota
+	// VerdictWin places the submission on the podium immediately, if a
+	// place is free.
+	VerdictWin
+	// VerdictPending means the submission is a candidate until Decide runs.
+	VerdictPending
+)
+
+// WinnerStrategy decides who fills the podium. The engine calls it only from
+// the event loop or with its lock held, so implementations need no locking
+// of their own.
+type WinnerStrategy interface {
+	// Offer is called with every judged submission in processing order.
+	Offer(s Submission) Verdict
+	// Decide is called when the round closes and returns further
+	// submissions to place on the podium, best first.
+	Decide() []Submission
+	// Reset discards all state before a round is played.
+	Reset()
+}
+
+// FirstCorrect places correct answers on the podium in the order they are
+// judged.
+func FirstCorrect() WinnerStrategy {
+	return firstCorrect{}
+}
+
+type firstCorrect struct{}
+
+func (firstCorrect) Offer(s Submission) Verdict {
+	if s.Correct {
+		return VerdictWin
+	}
+	return VerdictLose
+}
+
+func (firstCorrect) Decide() []Submission { return nil }
+func (firstCorrect) Reset()               {}
+
+// EarliestTimestamp ranks correct answers by trusted timestamp when the round
+// closes. Answers arriving more than window after the question opened are
+// ignored; a zero window accepts every answer.
+func EarliestTimestamp(window time.Duration) WinnerStrategy {
+	return &earliestTimestamp{window: window}
+}
+
+type earliestTimestamp struct {
+	window     time.Duration
+	candidates []Submission
+}
+
+func (e *earliestTimestamp) Offer(s Submission) Verdict {
+	if !s.Correct || (e.window > 0 && s.Elapsed > e.window) {
+		return VerdictLose
+	}
+	e.candidates = append(e.candidates, s)
+	return VerdictPending
+}
+
+func (e *earliestTimestamp) Decide() []Submission {
+	sort.SliceStable(e.candidates, func(i, j int) bool {
+		return e.candidates[i].At.Before(e.candidates[j].At)
+	})
+	return e.candidates
+}
+
+func (e *earliestTimestamp) Reset() { e.candidates = nil }
+
+// RandomDraw picks winners at random among correct answers received within
+// deadline of the question opening. A zero deadline accepts every answer
+// received before the round closes.
+func RandomDraw(deadline time.Duration, seed int64) WinnerStrategy {
+	return &randomDraw{deadline: deadline, rng: rand.New(rand.NewSource(seed))}
+}
+
+type randomDraw struct {
+	deadline   time.Duration
+	rng        *rand.Rand
+	candidates []Submission
+}
+
+func (r *randomDraw) Offer(s Submission) Verdict {
+	if !s.Correct || (r.deadline > 0 && s.Elapsed > r.deadline) {
+		return VerdictLose
+	}
+	r.candidates = append(r.candidates, s)
+	return VerdictPending
+}
+
+func (r *randomDraw) Decide() []Submission {
+	r.rng.Shuffle(len(r.candidates), func(i, j int) {
+		r.candidates[i], r.candidates[j] = r.candidates[j], r.candidates[i]
+	})
+	return r.candidates
+}
+
+func (r *randomDraw) Reset() { r.candidates = nil }
+
+// ClosestGuess ranks numeric answers by their distance from target, earliest
+// first on ties, ignoring the question's accepted answers. Non-numeric
+// answers never win.
+func ClosestGuess(target float64) WinnerStrategy {
+	return &closestGuess{target: target}
+}
+
+type closestGuess struct {
+	target  float64
+	guesses []guess
+}
+
+type guess struct {
+	Submission
+	distance float64
+}
+
+func (c *closestGuess) Offer(s Submission) Verdict {
+	value, err := strconv.ParseFloat(strings.TrimSpace(s.Response.Answer), 64)
+	if err != nil || math.IsNaN(value) {
+		return VerdictLose
+	}
+	c.guesses = append(c.guesses, guess{Submission: s, distance: math.Abs(value - c.target)})
+	return VerdictPending
+}
+
+func (c *closestGuess) Decide() []Submission {
+	sort.SliceStable(c.guesses, func(i, j int) bool {
+		if c.guesses[i].distance != c.guesses[j].distance {
+			return c.guesses[i].distance < c.guesses[j].distance
+		}
+		return c.guesses[i].At.Before(c.guesses[j].At)
+	})
+
+	ranked := make([]Submission, len(c.guesses))
+	for i, g := range c.guesses {
+		ranked[i] = g.Submission
+	}
+	return ranked
+}
+
+func (c *closestGuess) Reset() { c.guesses = nil }
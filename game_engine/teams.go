@@ -0,0 +1,127 @@
+package game_engine
+
+import (
+	"sort"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// TeamStanding is one team's position across the tournament, aggregated
+// from its members' individual results.
+type TeamStanding struct {
+	Rank    int    `json:"rank"`
+	TeamID  string `json:"team_id"`
+	Members int    `json:"members"`
+	// Points and Wins sum the members' leaderboard points and podium wins.
+	Points int `json:"points"`
+	Wins   int `json:"wins"`
+	// FirstCorrect counts the scored rounds in which a member was the first
+	// to answer correctly.
+	FirstCorrect int `json:"first_correct"`
+	Attempts     int `json:"attempts"`
+	Correct      int `json:"correct"`
+	// Accuracy is the percentage of the members' judged attempts that were
+	// correct.
+	Accuracy float64 `json:"accuracy"`
+}
+
+// AssignTeam puts userID on teamID, replacing any earlier assignment. An
+// empty teamID removes the user from their team. Assignments made here take
+// precedence over the team_id in a user's submissions.
+func (g *GameEngine) AssignTeam(userID int, teamID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if teamID == "" {
+		delete(g.teams, userID)
+		return
+	}
+	g.teams[userID] = teamID
+}
+
+// TeamOf returns the team userID plays for, or "".
+func (g *GameEngine) TeamOf(userID int) string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.teams[userID]
+}
+
+// TeamStandings returns the cumulative team standings, best first.
+func (g *GameEngine) TeamStandings() []TeamStanding {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.teamStandings()
+}
+
+// joinTeam resolves the team a submission counts for. A user without an
+// assignment joins the team named in their first submission that has one;
+// after that the payload's team_id is ignored. Must be called with g.mu
+// held.
+func (g *GameEngine) joinTeam(response api_server.UserResponse) string {
+	if team, ok := g.teams[response.UserID]; ok {
+		return team
+	}
+	if response.TeamID != "" {
+		g.teams[response.UserID] = response.TeamID
+	}
+	return response.TeamID
+}
+
+// teamStandings aggregates the leaderboard, the scored rounds and the user
+// records by team, ranking by points, then first-correct rounds, then
+// accuracy. Must be called with g.mu held.
+func (g *GameEngine) teamStandings() []TeamStanding {
+	if len(g.teams) == 0 {
+		return nil
+	}
+
+	byTeam := make(map[string]*TeamStanding)
+	for userID, teamID := range g.teams {
+		team := byTeam[teamID]
+		if team == nil {
+			team = &TeamStanding{TeamID: teamID}
+			byTeam[teamID] = team
+		}
+
+		team.Members++
+		team.Points += g.leaderboard[userID].Points
+		team.Wins += g.leaderboard[userID].Wins
+		if record, ok := g.users[userID]; ok {
+			team.Attempts += record.Attempts
+			team.Correct += record.Correct
+		}
+	}
+	for _, result := range g.results {
+		if team, ok := byTeam[result.FirstTeam]; ok {
+			team.FirstCorrect++
+		}
+	}
+
+	standings := make([]TeamStanding, 0, len(byTeam))
+	for _, team := range byTeam {
+		if team.Attempts > 0 {
+			team.Accuracy = float64(team.Correct) / float64(team.Attempts) * 100
+		}
+		standings = append(standings, *team)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.FirstCorrect != b.FirstCorrect {
+			return a.FirstCorrect > b.FirstCorrect
+		}
+		if a.Accuracy != b.Accuracy {
+			return a.Accuracy > b.Accuracy
+		}
+		return a.TeamID < b.TeamID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
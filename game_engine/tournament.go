@@ -0,0 +1,215 @@
+package game_engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// ErrNoMoreRounds is returned by NextRound once the last round has been scored.
+var ErrNoMoreRounds = errors.New("no more rounds in tournament")
+
+// Standing is one user's position in a round or across the tournament. Wins
+// counts podium finishes.
+type Standing struct {
+	Rank   int `json:"rank"`
+	UserID int `json:"user_id"`
+	Points int `json:"points"`
+	Wins   int `json:"wins"`
+}
+
+// RoundResult records the outcome of a scored round.
+type RoundResult struct {
+	Round            int                      `json:"round"`
+	QuestionID       string                   `json:"question_id"`
+	Winner           *api_server.UserResponse `json:"winner,omitempty"`
+	TimeToWin        time.Duration            `json:"time_to_win_ns"`
+	TotalResponses   int64                    `json:"total_responses"`
+	CorrectResponses int64                    `json:"correct_responses"`
+	Standings        []Standing               `json:"standings"`
+	// NoWinner records explicitly that the round closed with an empty podium.
+	NoWinner    bool   `json:"no_winner"`
+	CloseReason string `json:"close_reason"`
+	// FirstTeam is the team of the first user to be judged correct, if they
+	// were on one. That is judging order (trusted-time order in fairness
+	// mode), not the podium order a deferred strategy decides.
+	FirstTeam string `json:"first_team,omitempty"`
+	// Survivors is the number of users left in an elimination game after
+	// this round.
+	Survivors int `json:"survivors,omitempty"`
+}
+
+// scoreRound records the result of the current round and adds its points to
+// the leaderboard. In an elimination game it also eliminates the survivors
+// who did not answer correctly. Must be called with g.mu held.
+func (g *GameEngine) scoreRound() {
+	g.eliminateStragglers()
+
+	result := RoundResult{
+		Round:            g.current + 1,
+		QuestionID:       g.questions[g.current].ID,
+		TotalResponses:   atomic.LoadInt64(&g.totalResponses),
+		CorrectResponses: atomic.LoadInt64(&g.correctResponses),
+	}
+
+	if g.winner != nil {
+		winner := *g.winner
+		result.Winner = &winner
+		if g.winnerFoundAt != nil && g.startTime != nil {
+			result.TimeToWin = g.activeBetween(*g.startTime, *g.winnerFoundAt)
+		}
+	}
+	result.Standings = g.roundStandings()
+	result.NoWinner = len(g.podium) == 0
+	result.CloseReason = g.closeReason
+	result.FirstTeam = g.firstTeam
+	result.Survivors = g.survivorCount()
+
+	for _, s := range result.Standings {
+		entry := g.leaderboard[s.UserID]
+		entry.UserID = s.UserID
+		entry.Points += s.Points
+		entry.Wins += s.Wins
+		g.leaderboard[s.UserID] = entry
+	}
+	g.results = append(g.results, result)
+}
+
+// unscoreRound removes the current round's result and points, so a scored
+// round can be replayed after Reset. Must be called with g.mu held.
+func (g *GameEngine) unscoreRound() {
+	n := len(g.results)
+	if n == 0 || g.results[n-1].Round != g.current+1 {
+		return
+	}
+
+	for _, s := range g.results[n-1].Standings {
+		entry := g.leaderboard[s.UserID]
+		entry.Points -= s.Points
+		entry.Wins -= s.Wins
+		if entry.Points == 0 && entry.Wins == 0 {
+			delete(g.leaderboard, s.UserID)
+		} else {
+			g.leaderboard[s.UserID] = entry
+		}
+	}
+	g.results = g.results[:n-1]
+}
+
+// NextRound moves from a scored round to the next question in StatePending.
+// An elimination game ends once at most one survivor is left.
+func (g *GameEngine) NextRound() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state != StateScored {
+		return fmt.Errorf("%w: %s -> next round", ErrInvalidTransition, g.state)
+	}
+	if g.current+1 >= g.rounds {
+		return ErrNoMoreRounds
+	}
+	if n := g.survivorCount(); g.elimination.enabled && n <= 1 {
+		return fmt.Errorf("%w: %d survivors left", ErrNoMoreRounds, n)
+	}
+
+	g.current++
+	g.clearRound()
+	g.state = StatePending
+	g.emit(GameEvent{Type: EventRoundStarted, From: StateScored, To: StatePending, Time: g.clock.Now()})
+	return nil
+}
+
+// EndTournament freezes the leaderboard once the current round is scored.
+func (g *GameEngine) EndTournament() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.transition(StateFinished, GameEvent{Type: EventFinished})
+}
+
+// RestartTournament clears the leaderboard, user records and eliminations and
+// returns to the first round. Submissions not yet judged are turned away with
+// StatusReset.
+func (g *GameEngine) RestartTournament() {
+	g.resetRound(g.restartTournament)
+}
+
+// restartTournament does the work of RestartTournament. Must be called with
+// g.mu held.
+func (g *GameEngine) restartTournament() {
+	from := g.state
+	g.current = 0
+	g.results = nil
+	g.leaderboard = make(map[int]Standing)
+	g.users = make(userRecords)
+	g.elimination = newElimination(g.elimination.enabled)
+	g.clearRound()
+	g.state = StatePending
+	g.emit(GameEvent{Type: EventRoundStarted, From: from, To: StatePending, Time: g.clock.Now()})
+}
+
+// Leaderboard returns the cumulative tournament standings, best first.
+func (g *GameEngine) Leaderboard() []Standing {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.tournamentStandings()
+}
+
+// Results returns the results of every scored round so far.
+func (g *GameEngine) Results() []RoundResult {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	results := make([]RoundResult, len(g.results))
+	copy(results, g.results)
+	return results
+}
+
+// tournamentStandings ranks the leaderboard by points, then wins, then user
+// ID. Must be called with g.mu held.
+func (g *GameEngine) tournamentStandings() []Standing {
+	standings := make([]Standing, 0, len(g.leaderboard))
+	for _, s := range g.leaderboard {
+		standings = append(standings, s)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.UserID < b.UserID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
+
+// roundStandings returns the current round's standings: speed points when
+// latency-weighted scoring is on, podium points otherwise. Must be called
+// with g.mu held.
+func (g *GameEngine) roundStandings() []Standing {
+	if g.scoring.Enabled() {
+		return g.speedStandings()
+	}
+
+	standings := make([]Standing, 0, len(g.podium))
+	for _, entry := range g.podium {
+		standings = append(standings, Standing{
+			Rank:   entry.Rank,
+			UserID: entry.Response.UserID,
+			Points: g.pointsForRank(entry.Rank),
+			Wins:   1,
+		})
+	}
+	return standings
+}
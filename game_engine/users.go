@@ -0,0 +1,98 @@
+package game_engine
+
+import (
+	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/api_server"
+)
+
+// UserRecord is one user's submission history with an engine, kept across
+// rounds until the tournament is restarted. Submissions turned away by a
+// full queue never reach the engine and are not recorded.
+type UserRecord struct {
+	UserID int    `json:"user_id"`
+	TeamID string `json:"team_id,omitempty"`
+	// Submissions counts everything received from the user.
+	Submissions int `json:"submissions"`
+	// Attempts counts the submissions that were judged.
+	Attempts int `json:"attempts"`
+	Correct  int `json:"correct"`
+	// Rejected counts submissions turned away by the round state, the
+	// attempt policy or validation.
+	Rejected         int                     `json:"rejected"`
+	FirstSubmittedAt time.Time               `json:"first_submitted_at"`
+	LastSubmittedAt  time.Time               `json:"last_submitted_at"`
+	LastAnswer       string                  `json:"last_answer"`
+	LastStatus       api_server.SubmitStatus `json:"last_status"`
+	// LastLatency and AvgLatency measure from receipt to verdict.
+	LastLatency time.Duration `json:"last_latency_ns"`
+	AvgLatency  time.Duration `json:"avg_latency_ns"`
+	// Rank is the user's podium rank in the current round, or 0.
+	Rank int `json:"rank"`
+	// TournamentRank is the user's place on the leaderboard, or 0.
+	TournamentRank int `json:"tournament_rank"`
+	// EliminatedIn is the round the user was knocked out of an elimination
+	// game in, or 0.
+	EliminatedIn int `json:"eliminated_in,omitempty"`
+}
+
+// userRecord is a UserRecord plus what is needed to keep it up to date.
+type userRecord struct {
+	UserRecord
+	totalLatency time.Duration
+}
+
+// userRecords maps user IDs to their records.
+type userRecords map[int]*userRecord
+
+// received records a submission arriving at, creating the user's record on
+// first contact, and returns the record.
+func (u userRecords) received(response api_server.UserResponse, at time.Time) *userRecord {
+	record := u[response.UserID]
+	if record == nil {
+		record = &userRecord{UserRecord: UserRecord{UserID: response.UserID, FirstSubmittedAt: at}}
+		u[response.UserID] = record
+	}
+
+	record.Submissions++
+	record.LastSubmittedAt = at
+	record.LastAnswer = response.Answer
+	return record
+}
+
+// answered records the verdict a submission received at was given at now.
+func (r *userRecord) answered(result api_server.SubmitResult, at, now time.Time) {
+	r.LastStatus = result.Status
+	r.LastLatency = now.Sub(at)
+	r.totalLatency += r.LastLatency
+	r.AvgLatency = r.totalLatency / time.Duration(r.Submissions)
+}
+
+// GetUserRecord returns the submission history of userID, or false if the
+// engine has never received a submission from them.
+func (g *GameEngine) GetUserRecord(userID int) (UserRecord, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	record, ok := g.users[userID]
+	if !ok {
+		return UserRecord{}, false
+	}
+
+	user := record.UserRecord
+	user.TeamID = g.teams[userID]
+	user.EliminatedIn = g.elimination.eliminatedIn[userID]
+	user.Rank = g.rankOf(userID)
+	for _, s := range g.tournamentStandings() {
+		if s.UserID == userID {
+			user.TournamentRank = s.Rank
+			break
+		}
+	}
+	return user, true
+}
+
+// UserSnapshot implements api_server.GameEngineInterface.
+func (g *GameEngine) UserSnapshot(userID int) (interface{}, bool) {
+	return g.GetUserRecord(userID)
+}
@@ -1,3 +1,8 @@
 module github.com/glitchdawg/game-engine-with-user
 
 go 1.24.4
+
+require (
+	golang.org/x/text v0.32.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -2,11 +2,14 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -21,32 +24,76 @@ func main() {
 	var port string
 	var numUsers int
 	var apiURL string
+	var timeLimit time.Duration
+	var elimination bool
+	var gameFile string
+	var startAt string
 
 	flag.StringVar(&mode, "mode", "server", "Mode: server, mock, or full")
 	flag.StringVar(&port, "port", "8080", "API server port")
 	flag.IntVar(&numUsers, "users", 1000, "Number of mock users")
 	flag.StringVar(&apiURL, "api", "http://localhost:8080/submit", "API URL for mock engine")
+	flag.DurationVar(&timeLimit, "limit", 0, "Time limit per question, e.g. 30s (0 = no limit)")
+	flag.BoolVar(&elimination, "elimination", false, "Eliminate users on a wrong answer")
+	flag.StringVar(&gameFile, "game", "", "Game definition file (YAML or JSON)")
+	flag.StringVar(&startAt, "start", "", "Open the first question at this time (RFC 3339) or after this delay, e.g. 2m")
 	flag.Parse()
 
+	var start time.Time
+	if startAt != "" {
+		var err error
+		if start, err = parseStart(startAt); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	baseConfig := game_engine.DefaultConfig
+	if gameFile != "" {
+		def, err := game_engine.LoadGameDefinition(gameFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		baseConfig = def.Config
+	}
+
+	newConfig := func() game_engine.Config {
+		cfg := baseConfig()
+		if timeLimit > 0 {
+			cfg.TimeLimit = timeLimit
+		}
+		if elimination {
+			cfg.Elimination = true
+		}
+		if !start.IsZero() {
+			cfg.StartAt = start
+		}
+		return cfg
+	}
+
 	switch mode {
 	case "server":
-		runInteractiveServer(port)
+		runInteractiveServer(port, newConfig)
 	case "mock":
-		runMockEngine(numUsers, apiURL)
+		runMockEngine(numUsers, apiURL, newConfig().Questions[0])
 	case "full":
-		runFullSimulation(port, numUsers)
+		runFullSimulation(port, numUsers, newConfig)
 	default:
 		fmt.Println("Invalid mode. Use: server, mock, or full")
 		os.Exit(1)
 	}
 }
 
-func runInteractiveServer(port string) {
+func runInteractiveServer(port string, newConfig func() game_engine.Config) {
 	clearScreen()
 	printBanner("GAME SERVER")
-	
-	engine := game_engine.NewGameEngine()
-	server := api_server.NewAPIServer(port, engine)
+
+	registry := game_engine.NewRegistry(context.Background(), newConfig)
+	engine, err := registry.Create(api_server.DefaultGameID, newConfig())
+	if err != nil {
+		log.Fatal("Failed to create game:", err)
+	}
+	server := api_server.NewAPIServerWithRegistry(port, registry)
+	openUnlessScheduled(engine)
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -58,13 +105,20 @@ func runInteractiveServer(port string) {
 	}()
 
 	time.Sleep(1 * time.Second)
-	
+
 	fmt.Printf("✅ Server running on port %s\n", port)
 	fmt.Printf("📍 Endpoint: POST http://localhost:%s/submit\n", port)
+	fmt.Printf("📍 Rooms:    GET/POST http://localhost:%s/games, POST /games/{id}/submit\n", port)
 	fmt.Println("\n╔════════════════════════════════════╗")
 	fmt.Println("║         AVAILABLE COMMANDS         ║")
 	fmt.Println("╠════════════════════════════════════╣")
 	fmt.Println("║  stats  - Show current statistics  ║")
+	fmt.Println("║  open   - Open the current question║")
+	fmt.Println("║  close  - Close current question   ║")
+	fmt.Println("║  pause  - Pause current question   ║")
+	fmt.Println("║  resume - Resume paused question   ║")
+	fmt.Println("║  next   - Score, start next round  ║")
+	fmt.Println("║  board  - Show tournament standings║")
 	fmt.Println("║  reset  - Reset the game engine    ║")
 	fmt.Println("║  clear  - Clear the screen         ║")
 	fmt.Println("║  exit   - Shutdown server          ║")
@@ -74,7 +128,7 @@ func runInteractiveServer(port string) {
 
 	go func() {
 		<-sigChan
-		handleShutdown(engine)
+		handleShutdown(registry)
 	}()
 
 	scanner := bufio.NewScanner(os.Stdin)
@@ -83,19 +137,38 @@ func runInteractiveServer(port string) {
 		if !scanner.Scan() {
 			break
 		}
-		
+
 		command := strings.TrimSpace(strings.ToLower(scanner.Text()))
-		
+
 		switch command {
 		case "stats":
 			showStats(engine)
+		case "open":
+			openQuestion(engine)
+		case "close":
+			if err := engine.Close(); err != nil {
+				fmt.Printf("Cannot close: %v\n", err)
+			}
+		case "pause":
+			if err := engine.Pause(); err != nil {
+				fmt.Printf("Cannot pause: %v\n", err)
+			}
+		case "resume":
+			if err := engine.Resume(); err != nil {
+				fmt.Printf("Cannot resume: %v\n", err)
+			}
+		case "next":
+			nextRound(engine)
+		case "board":
+			showLeaderboard(engine)
 		case "reset":
 			engine.Reset()
+			openQuestion(engine)
 		case "clear":
 			clearScreen()
 			printBanner("GAME SERVER")
 		case "exit", "quit":
-			handleShutdown(engine)
+			handleShutdown(registry)
 		case "":
 			continue
 		default:
@@ -104,20 +177,21 @@ func runInteractiveServer(port string) {
 	}
 }
 
-func runMockEngine(numUsers int, apiURL string) {
+func runMockEngine(numUsers int, apiURL string, question game_engine.Question) {
 	clearScreen()
 	printBanner("MOCK USER ENGINE")
-	
+
 	fmt.Printf("📊 Simulating %d users\n", numUsers)
 	fmt.Printf("🎯 Target API: %s\n", apiURL)
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	engine := mock_engine.NewMockEngine(apiURL)
+	engine.SetAnswers(mockAnswers(question))
 	start := time.Now()
-	
+
 	fmt.Println("\n⚡ Starting simulation...")
 	engine.SimulateUsers(numUsers)
-	
+
 	duration := time.Since(start)
 	fmt.Println("\n╔════════════════════════════════════╗")
 	fmt.Println("║       SIMULATION COMPLETE          ║")
@@ -128,16 +202,17 @@ func runMockEngine(numUsers int, apiURL string) {
 	fmt.Println("╚════════════════════════════════════╝")
 }
 
-func runFullSimulation(port string, numUsers int) {
+func runFullSimulation(port string, numUsers int, newConfig func() game_engine.Config) {
 	clearScreen()
 	printBanner("FULL SIMULATION")
-	
+
 	fmt.Printf("🖥️  Server Port: %s\n", port)
 	fmt.Printf("👥 Mock Users: %d\n", numUsers)
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
-	engine := game_engine.NewGameEngine()
+	engine := game_engine.NewGameEngineWithConfig(context.Background(), newConfig())
 	server := api_server.NewAPIServer(port, engine)
+	openUnlessScheduled(engine)
 
 	go func() {
 		if err := server.Start(); err != nil {
@@ -151,96 +226,211 @@ func runFullSimulation(port string, numUsers int) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	mockEngine := mock_engine.NewMockEngine("http://localhost:" + port + "/submit")
-	
+	mockEngine.SetAnswers(mockAnswers(engine.CurrentQuestion()))
+
 	start := time.Now()
 	mockEngine.SimulateUsers(numUsers)
-	
+
 	time.Sleep(2 * time.Second)
-	
-	displayFinalResults(engine, start)
+
+	ctx, cancel := context.WithTimeout(context.Background(), game_engine.DefaultDrainTimeout)
+	defer cancel()
+	summary, err := engine.Stop(ctx)
+	if err != nil {
+		fmt.Printf("Stopped with %d submissions abandoned: %v\n", summary.Abandoned, err)
+	}
+
+	displayFinalResults(summary, start)
+}
+
+// mockAnswers returns the answers simulated users give to q when they mean to
+// be right and wrong. A nil list keeps the simulator's default.
+func mockAnswers(q game_engine.Question) (correct, incorrect []string) {
+	switch q.Type {
+	case game_engine.TypeSingleChoice, game_engine.TypeMultiChoice:
+		for _, choice := range q.Choices {
+			if !slices.Contains(q.AcceptedAnswers, choice) {
+				incorrect = append(incorrect, choice)
+			}
+		}
+		if q.Type == game_engine.TypeMultiChoice {
+			return []string{strings.Join(q.AcceptedAnswers, ",")}, incorrect
+		}
+		return q.AcceptedAnswers, incorrect
+	case game_engine.TypeOrdering:
+		reversed := slices.Clone(q.AcceptedAnswers)
+		slices.Reverse(reversed)
+		return []string{strings.Join(q.AcceptedAnswers, ",")}, []string{strings.Join(reversed, ",")}
+	case game_engine.TypeNumeric:
+		if len(q.AcceptedAnswers) == 0 && q.Range != nil {
+			return []string{strconv.FormatFloat(q.Range.Min, 'g', -1, 64)}, nil
+		}
+	}
+	return q.AcceptedAnswers, nil
+}
+
+func openQuestion(engine *game_engine.GameEngine) {
+	if err := engine.Open(); err != nil {
+		fmt.Printf("Cannot open: %v\n", err)
+	}
+}
+
+// openUnlessScheduled opens the first question now, or reports the countdown
+// if it is scheduled to open later.
+func openUnlessScheduled(engine *game_engine.GameEngine) {
+	if engine.State() == game_engine.StateCountdown {
+		fmt.Printf("⏳ First question opens in %v\n", engine.StartsIn().Round(time.Second))
+		return
+	}
+	openQuestion(engine)
+}
+
+// parseStart reads the -start flag: an RFC 3339 time, or a delay from now.
+func parseStart(value string) (time.Time, error) {
+	if delay, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(delay), nil
+	}
+	start, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid -start %q: want an RFC 3339 time or a delay such as 2m", value)
+	}
+	return start, nil
+}
+
+func nextRound(engine *game_engine.GameEngine) {
+	if state := engine.State(); state == game_engine.StateOpen || state == game_engine.StatePaused {
+		engine.Close()
+	}
+	if engine.State() == game_engine.StateClosed {
+		engine.Score()
+	}
+	if err := engine.NextRound(); err != nil {
+		fmt.Printf("Cannot start next round: %v\n", err)
+		return
+	}
+	openQuestion(engine)
+}
+
+func showLeaderboard(engine *game_engine.GameEngine) {
+	fmt.Println("\n╔════════════════════════════════════╗")
+	fmt.Println("║       TOURNAMENT STANDINGS         ║")
+	fmt.Println("╠════════════════════════════════════╣")
+
+	standings := engine.Leaderboard()
+	if len(standings) == 0 {
+		fmt.Println("║ No points awarded yet              ║")
+	}
+	for _, s := range standings {
+		fmt.Printf("║ #%-3d User %-10d %6d pts    ║\n", s.Rank, s.UserID, s.Points)
+	}
+
+	if teams := engine.TeamStandings(); len(teams) > 0 {
+		fmt.Println("╠════════════════════════════════════╣")
+		fmt.Println("║ TEAMS        pts  first  accuracy  ║")
+		for _, t := range teams {
+			fmt.Printf("║ #%-3d %-8.8s %4d  %5d  %7.1f%%  ║\n", t.Rank, t.TeamID, t.Points, t.FirstCorrect, t.Accuracy)
+		}
+	}
+
+	fmt.Println("╚════════════════════════════════════╝")
 }
 
 func showStats(engine *game_engine.GameEngine) {
 	stats := engine.GetStats()
-	
+
 	fmt.Println("\n╔════════════════════════════════════╗")
 	fmt.Println("║         CURRENT STATISTICS        ║")
 	fmt.Println("╠════════════════════════════════════╣")
-	
-	total := stats["total_responses"].(int64)
-	correct := stats["correct_responses"].(int64)
-	duration := stats["game_duration"].(float64)
-	
-	fmt.Printf("║ Total Responses: %-18d ║\n", total)
-	fmt.Printf("║ Correct Responses: %-16d ║\n", correct)
-	
-	if total > 0 {
-		percentage := stats["correct_percentage"].(float64)
-		fmt.Printf("║ Success Rate: %.1f%%                ║\n", percentage)
+
+	fmt.Printf("║ Total Responses: %-18d ║\n", stats.TotalResponses)
+	fmt.Printf("║ Correct Responses: %-16d ║\n", stats.CorrectResponses)
+
+	if stats.TotalResponses > 0 {
+		fmt.Printf("║ Success Rate: %.1f%%                ║\n", stats.CorrectPercentage)
+	}
+
+	fmt.Printf("║ Duration: %.1fs                     ║\n", stats.GameDuration)
+	fmt.Printf("║ Throughput: %.1f resp/s            ║\n", stats.ResponsesPerSec)
+	fmt.Printf("║ Queue depth: %-21d ║\n", stats.QueueDepth)
+	if stats.StartsIn > 0 {
+		fmt.Printf("║ Starts in: %-22.1fs ║\n", stats.StartsIn)
 	}
-	
-	fmt.Printf("║ Duration: %.1fs                     ║\n", duration)
-	
-	if stats["has_winner"].(bool) {
+	if stats.Deadline != nil {
+		fmt.Printf("║ Time remaining: %-17.1fs ║\n", stats.TimeRemaining)
+	}
+
+	if stats.Survivors > 0 || stats.Eliminated > 0 {
+		fmt.Printf("║ Survivors: %-10d Out: %-8d ║\n", stats.Survivors, stats.Eliminated)
+	}
+	if stats.FirstTeam != "" {
+		fmt.Printf("║ First team: %-22.22s ║\n", stats.FirstTeam)
+	}
+
+	if stats.HasWinner {
+		fmt.Println("╠════════════════════════════════════╣")
+		fmt.Printf("║ 🏆 Winner: User %-18d ║\n", stats.WinnerUserID)
+		fmt.Printf("║ Answer: %-27s ║\n", stats.WinnerAnswer)
+		fmt.Printf("║ Time to win: %.3fs                ║\n", stats.TimeToWin)
+		fmt.Printf("║ Winner latency: %.3fms           ║\n", stats.WinnerLatency*1000)
+	} else if stats.NoWinner {
 		fmt.Println("╠════════════════════════════════════╣")
-		fmt.Printf("║ 🏆 Winner: User %-18d ║\n", stats["winner_user_id"])
-		fmt.Printf("║ Answer: %-27s ║\n", stats["winner_answer"])
-		timeToWin := stats["time_to_win"].(float64)
-		fmt.Printf("║ Time to win: %.3fs                ║\n", timeToWin)
+		fmt.Println("║ ❌ Closed with no winner           ║")
 	} else {
 		fmt.Println("╠════════════════════════════════════╣")
 		fmt.Println("║ ⏳ No winner yet                   ║")
 	}
-	
+
 	fmt.Println("╚════════════════════════════════════╝")
 }
 
-func displayFinalResults(engine *game_engine.GameEngine, startTime time.Time) {
-	stats := engine.GetStats()
-	
+func displayFinalResults(summary game_engine.Summary, startTime time.Time) {
+	stats := summary.Stats
+
 	fmt.Println("\n╔════════════════════════════════════════╗")
 	fmt.Println("║          FINAL RESULTS                ║")
 	fmt.Println("╠════════════════════════════════════════╣")
-	
-	if stats["has_winner"].(bool) {
-		fmt.Printf("║ 🏆 WINNER: User %-22d ║\n", stats["winner_user_id"])
-		fmt.Printf("║    Answer: %-27s ║\n", stats["winner_answer"])
-		timeToWin := stats["time_to_win"].(float64)
-		fmt.Printf("║    Time to win: %.3f seconds         ║\n", timeToWin)
+
+	if stats.HasWinner {
+		fmt.Printf("║ 🏆 WINNER: User %-22d ║\n", stats.WinnerUserID)
+		fmt.Printf("║    Answer: %-27s ║\n", stats.WinnerAnswer)
+		fmt.Printf("║    Time to win: %.3f seconds         ║\n", stats.TimeToWin)
 	} else {
 		fmt.Println("║ ❌ No winner found (no correct answers) ║")
 	}
-	
+
 	fmt.Println("╠════════════════════════════════════════╣")
 	fmt.Println("║            STATISTICS                 ║")
 	fmt.Println("╠════════════════════════════════════════╣")
-	
-	total := stats["total_responses"].(int64)
-	correct := stats["correct_responses"].(int64)
-	
-	fmt.Printf("║ Total Responses: %-21d ║\n", total)
-	fmt.Printf("║ Correct Responses: %-19d ║\n", correct)
-	
-	if total > 0 {
-		percentage := stats["correct_percentage"].(float64)
-		fmt.Printf("║ Success Rate: %.2f%%                   ║\n", percentage)
+
+	fmt.Printf("║ Total Responses: %-21d ║\n", stats.TotalResponses)
+	fmt.Printf("║ Correct Responses: %-19d ║\n", stats.CorrectResponses)
+
+	if stats.TotalResponses > 0 {
+		fmt.Printf("║ Success Rate: %.2f%%                   ║\n", stats.CorrectPercentage)
 	}
-	
+
 	fmt.Printf("║ Total Time: %.3f seconds              ║\n", time.Since(startTime).Seconds())
 	fmt.Println("╚════════════════════════════════════════╝")
 }
 
-func handleShutdown(engine *game_engine.GameEngine) {
+func handleShutdown(registry *game_engine.Registry) {
 	fmt.Println("\n🛑 Shutting down server...")
-	
-	if winner := engine.GetWinner(); winner != nil {
-		fmt.Printf("Final Winner: User %d with answer '%s'\n", winner.UserID, winner.Answer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), game_engine.DefaultDrainTimeout)
+	summaries, err := registry.Shutdown(ctx)
+	cancel()
+	if err != nil {
+		fmt.Printf("Shutdown did not drain cleanly: %v\n", err)
 	}
-	
-	stats := engine.GetStats()
-	fmt.Printf("Total responses processed: %d\n", stats["total_responses"])
-	
-	engine.Shutdown()
+
+	if summary, ok := summaries[api_server.DefaultGameID]; ok {
+		stats := summary.Stats
+		if stats.HasWinner {
+			fmt.Printf("Final Winner: User %d with answer '%s'\n", stats.WinnerUserID, stats.WinnerAnswer)
+		}
+		fmt.Printf("Total responses processed: %d (%d drained at shutdown)\n", stats.TotalResponses, summary.Drained)
+	}
+
 	os.Exit(0)
 }
 
@@ -253,4 +443,4 @@ func printBanner(title string) {
 	fmt.Printf("║         %-32s ║\n", title)
 	fmt.Println("╚══════════════════════════════════════════╝")
 	fmt.Println()
-}
\ No newline at end of file
+}
@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/glitchdawg/game-engine-with-user/clock"
 )
 
 type UserResponse struct {
@@ -18,19 +20,43 @@ type UserResponse struct {
 }
 
 type MockEngine struct {
-	apiURL string
-	wg     sync.WaitGroup
+	apiURL           string
+	wg               sync.WaitGroup
+	clock            clock.Clock
+	correctAnswers   []string
+	incorrectAnswers []string
 }
 
 func NewMockEngine(apiURL string) *MockEngine {
 	return &MockEngine{
-		apiURL: apiURL,
+		apiURL:           apiURL,
+		clock:            clock.Real{},
+		correctAnswers:   []string{"42", "correct", "true", "yes"},
+		incorrectAnswers: []string{"41", "wrong", "false", "no", "maybe", "unknown"},
+	}
+}
+
+// SetAnswers replaces the answers simulated users pick from when they mean
+// to be right and wrong. An empty list keeps the current one.
+func (m *MockEngine) SetAnswers(correct, incorrect []string) {
+	if len(correct) > 0 {
+		m.correctAnswers = correct
 	}
+	if len(incorrect) > 0 {
+		m.incorrectAnswers = incorrect
+	}
+}
+
+// SetClock replaces the time source used for user delays and response
+// timestamps. With a clock.Fake, simulated users wait until the clock is
+// advanced past their delay.
+func (m *MockEngine) SetClock(c clock.Clock) {
+	m.clock = clock.Or(c)
 }
 
 func (m *MockEngine) SimulateUsers(numUsers int) {
 	fmt.Printf("Starting simulation for %d users...\n", numUsers)
-	startTime := time.Now()
+	startTime := m.clock.Now()
 
 	for i := 1; i <= numUsers; i++ {
 		m.wg.Add(1)
@@ -38,7 +64,7 @@ func (m *MockEngine) SimulateUsers(numUsers int) {
 	}
 
 	m.wg.Wait()
-	fmt.Printf("All %d users have sent their responses. Time taken: %v\n", numUsers, time.Since(startTime))
+	fmt.Printf("All %d users have sent their responses. Time taken: %v\n", numUsers, m.clock.Since(startTime))
 }
 
 func (m *MockEngine) simulateUser(userID int) {
@@ -47,13 +73,13 @@ func (m *MockEngine) simulateUser(userID int) {
 	isCorrect := rand.Float32() < 0.3
 	
 	delay := time.Duration(rand.Intn(991)+10) * time.Millisecond
-	time.Sleep(delay)
+	m.clock.Sleep(delay)
 
 	response := UserResponse{
 		UserID:    userID,
-		Answer:    generateAnswer(isCorrect),
+		Answer:    m.generateAnswer(isCorrect),
 		IsCorrect: isCorrect,
-		Timestamp: time.Now().UnixNano(),
+		Timestamp: m.clock.Now().UnixNano(),
 	}
 
 	if err := m.sendResponse(response); err != nil {
@@ -80,12 +106,9 @@ func (m *MockEngine) sendResponse(response UserResponse) error {
 	return nil
 }
 
-func generateAnswer(isCorrect bool) string {
-	correctAnswers := []string{"42", "correct", "true", "yes"}
-	incorrectAnswers := []string{"41", "wrong", "false", "no", "maybe", "unknown"}
-
+func (m *MockEngine) generateAnswer(isCorrect bool) string {
 	if isCorrect {
-		return correctAnswers[rand.Intn(len(correctAnswers))]
+		return m.correctAnswers[rand.Intn(len(m.correctAnswers))]
 	}
-	return incorrectAnswers[rand.Intn(len(incorrectAnswers))]
+	return m.incorrectAnswers[rand.Intn(len(m.incorrectAnswers))]
 }
\ No newline at end of file